@@ -0,0 +1,126 @@
+package pas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxReportedWaits is the number of tasks listed in Report.Waits.
+const maxReportedWaits = 10
+
+// Report summarizes how a finished graph of Async calls used the available parallelism.
+type Report struct {
+	// Wall is the time from the first task's creation to the last task's completion.
+	Wall time.Duration
+	// Work is the sum of the execution times of all tasks.
+	Work time.Duration
+	// Parallelism is the achieved average parallelism, Work divided by Wall.
+	Parallelism float64
+	// CriticalPath is the chain of dependencies that set the total wall time,
+	// from the first task to the last one to finish.
+	CriticalPath []Node
+	// CriticalWork is the sum of the execution times of the tasks on the critical path.
+	// It is a lower bound on Wall for any scheduling of the same chain.
+	CriticalWork time.Duration
+	// Waits lists the tasks whose wait time most exceeded their run time, worst first.
+	Waits []TaskWait
+}
+
+// TaskWait describes how long a task waited before running, compared to how long it ran.
+type TaskWait struct {
+	Node Node
+	Wait time.Duration // From creation until the function started
+	Run  time.Duration // From the function's start until it returned
+}
+
+// Analyze computes the critical path and parallelism of a recorded graph.
+// Nodes that never started, such as promises created by New, are not counted as work.
+func (g Graph) Analyze() Report {
+	var r Report
+	nodes := make(map[uint64]Node, len(g.Nodes))
+	var first, last time.Time
+	var end Node
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+		if n.Started.IsZero() {
+			continue
+		}
+		run := n.Finished.Sub(n.Started)
+		r.Work += run
+		if first.IsZero() || n.Created.Before(first) {
+			first = n.Created
+		}
+		if n.Finished.After(last) {
+			last = n.Finished
+			end = n
+		}
+		if wait := n.Started.Sub(n.Created); wait > run {
+			r.Waits = append(r.Waits, TaskWait{Node: n, Wait: wait, Run: run})
+		}
+	}
+	if last.IsZero() {
+		return r
+	}
+	r.Wall = last.Sub(first)
+	if r.Wall > 0 {
+		r.Parallelism = float64(r.Work) / float64(r.Wall)
+	}
+	sort.Slice(r.Waits, func(i, j int) bool {
+		return r.Waits[i].Wait-r.Waits[i].Run > r.Waits[j].Wait-r.Waits[j].Run
+	})
+	if len(r.Waits) > maxReportedWaits {
+		r.Waits = r.Waits[:maxReportedWaits]
+	}
+
+	// Walk back from the last task to finish, always following the dependency that finished last,
+	// since that is the one the task was waiting for.
+	preds := make(map[uint64][]uint64)
+	for _, e := range g.Edges {
+		preds[e.To] = append(preds[e.To], e.From)
+	}
+	for n, ok := end, true; ok; {
+		r.CriticalPath = append(r.CriticalPath, n)
+		r.CriticalWork += n.Finished.Sub(n.Started)
+		var next Node
+		ok = false
+		for _, id := range preds[n.ID] {
+			if p := nodes[id]; !p.Started.IsZero() && (!ok || p.Finished.After(next.Finished)) {
+				next, ok = p, true
+			}
+		}
+		n = next
+	}
+	for i, j := 0, len(r.CriticalPath)-1; i < j; i, j = i+1, j-1 {
+		r.CriticalPath[i], r.CriticalPath[j] = r.CriticalPath[j], r.CriticalPath[i]
+	}
+	return r
+}
+
+// Analyze computes the critical path and parallelism of the graph captured so far.
+// It is a shorthand for r.Graph().Analyze().
+func (r *Recorder) Analyze() Report {
+	return r.Graph().Analyze()
+}
+
+// String formats the report as human-readable text.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "wall time:    %v\n", r.Wall)
+	fmt.Fprintf(&b, "total work:   %v\n", r.Work)
+	fmt.Fprintf(&b, "parallelism:  %.2f\n", r.Parallelism)
+	fmt.Fprintf(&b, "critical path (%d tasks, %v of work):\n", len(r.CriticalPath), r.CriticalWork)
+	for _, n := range r.CriticalPath {
+		fmt.Fprintf(&b, "  %-40s run %-12v waited %v\n",
+			fmt.Sprintf("%s #%d", n.shortName(), n.ID), n.Finished.Sub(n.Started), n.Started.Sub(n.Created))
+	}
+	if len(r.Waits) > 0 {
+		b.WriteString("tasks waiting longer than they ran:\n")
+		for _, w := range r.Waits {
+			fmt.Fprintf(&b, "  %-40s run %-12v waited %v\n",
+				fmt.Sprintf("%s #%d", w.Node.shortName(), w.Node.ID), w.Run, w.Wait)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package pas
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func SlowAdd(a, b int) int {
+	time.Sleep(10 * time.Millisecond)
+	return a + b
+}
+
+// TestAnalyzeLinearChain verifies that the critical path of a linear chain covers the whole chain.
+func TestAnalyzeLinearChain(t *testing.T) {
+	rec := StartRecording()
+	p := New(0)
+	var chain []uint64
+	for i := 1; i <= 3; i++ {
+		p = Async[int](SlowAdd, p, i)
+		chain = append(chain, p.id)
+	}
+	p.Get()
+	rec.Stop()
+
+	r := rec.Analyze()
+	if len(r.CriticalPath) != len(chain) {
+		t.Fatalf("Expected critical path of %d tasks, got %d:\n%s", len(chain), len(r.CriticalPath), r)
+	}
+	for i, n := range r.CriticalPath {
+		if n.ID != chain[i] {
+			t.Errorf("Expected task #%d at position %d of the critical path, got #%d", chain[i], i, n.ID)
+		}
+	}
+	if r.CriticalWork < 30*time.Millisecond || r.Wall < r.CriticalWork {
+		t.Errorf("Expected critical work of at least 30ms within wall time, got %v of %v", r.CriticalWork, r.Wall)
+	}
+	if len(r.Waits) == 0 {
+		t.Errorf("Expected the last tasks of the chain to wait longer than they ran")
+	}
+	if !strings.Contains(r.String(), "SlowAdd") {
+		t.Errorf("Expected report to mention SlowAdd:\n%s", r)
+	}
+}
+
+// TestAnalyzeIndependentTasks verifies that independent tasks yield a one-task critical path.
+func TestAnalyzeIndependentTasks(t *testing.T) {
+	rec := StartRecording()
+	arr := MakeSlice[int](4)
+	for i := range arr {
+		arr[i] = Async[int](SlowAdd, i, i)
+	}
+	for _, p := range arr {
+		p.Get()
+	}
+	rec.Stop()
+
+	r := rec.Analyze()
+	if len(r.CriticalPath) != 1 {
+		t.Errorf("Expected a critical path of 1 task, got %d:\n%s", len(r.CriticalPath), r)
+	}
+	if r.Parallelism <= 1 {
+		t.Errorf("Expected parallelism above 1 for sleeping tasks, got %.2f", r.Parallelism)
+	}
+}
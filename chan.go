@@ -0,0 +1,98 @@
+package pas
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+)
+
+// ErrChanClosed rejects a Promise created by FromChan whose channel was closed before sending a value.
+var ErrChanClosed = errors.New("pas: channel closed before sending a value")
+
+// FromChan returns a Promise resolved with the first value received from ch,
+// or rejected with ErrChanClosed if ch is closed first.
+// Usage example: p := pas.FromChan(results)
+func FromChan[T any](ch <-chan T) *Promise[T] {
+	p := newPending[T]()
+	go func() {
+		value, ok := <-ch
+		if !ok {
+			p.settle(value, ErrChanClosed)
+			return
+		}
+		p.settle(value, nil)
+	}()
+	return p
+}
+
+// ToChan returns a channel that receives the value of p once it is resolved, and is then closed.
+// If p is rejected, the channel is closed without receiving a value; use Promise.Err to tell why.
+// The channel is buffered, so p's value is delivered even if nobody receives it.
+// Usage example: select { case v := <-pas.ToChan(p): ...; case <-ctx.Done(): ... }
+func ToChan[T any](p *Promise[T]) <-chan T {
+	ch := make(chan T, 1)
+	go func() {
+		<-p.ready
+		if p.err == nil {
+			ch <- p.value
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+// bridgeChan returns a channel of type expectedType, fed with the elements received from input
+// as each is resolved, in the order they are received. The channel is closed when input is.
+// It allows passing a chan *Promise[T] to a function taking a chan T or a <-chan T.
+// If an element is rejected, the channel is closed early, and the task is rejected with the element's error
+// once its function returns, as if the element had been rejected before the call.
+// The feeding stops, and the channel is closed, once the task is done, even if its function did not drain it.
+func bridgeChan(t *task, input reflect.Value, expectedType reflect.Type) reflect.Value {
+	elemType := expectedType.Elem()
+	out := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, elemType), input.Cap())
+	var failure atomic.Pointer[error]
+	t.lateArgs = append(t.lateArgs, func() error {
+		if err := failure.Load(); err != nil {
+			return *err
+		}
+		return nil
+	})
+	stop, cancel := context.WithCancel(context.Background())
+	t.cleanups = append(t.cleanups, cancel)
+	done := reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop.Done())}
+
+	// The elements are resolved after the task's arguments, so their wait is not bounded by the wait budget,
+	// but only by the task being done
+	feeder := *t
+	feeder.waitCtx = stop
+	go func() {
+		defer out.Close()
+		for {
+			chosen, elem, ok := reflect.Select([]reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: input}, done})
+			if chosen == 1 || !ok {
+				return
+			}
+			resolved, err := resolveValue(&feeder, elem.Interface(), elemType)
+			if stop.Err() != nil {
+				return
+			}
+			if err != nil {
+				var rejected *rejectedError
+				if errors.As(err, &rejected) {
+					err = rejected.err
+				}
+				failure.Store(&err)
+				return
+			}
+			value := reflect.Zero(elemType)
+			if resolved != nil {
+				value = reflect.ValueOf(resolved)
+			}
+			if chosen, _, _ := reflect.Select([]reflect.SelectCase{{Dir: reflect.SelectSend, Chan: out, Send: value}, done}); chosen == 1 {
+				return
+			}
+		}
+	}()
+	return out.Convert(expectedType)
+}
@@ -0,0 +1,102 @@
+package pas
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestFromChan verifies that a promise is resolved by the first value of a channel,
+// or rejected if the channel is closed first.
+func TestFromChan(t *testing.T) {
+	ch := make(chan int)
+	p := FromChan(ch)
+	sum := Async[int](Add, p, 1)
+	ch <- 41
+	if val := sum.Get(); val != 42 {
+		t.Errorf("Expected 42, got %d", val)
+	}
+
+	closed := make(chan int)
+	close(closed)
+	if err := FromChan(closed).Err(); !errors.Is(err, ErrChanClosed) {
+		t.Errorf("Expected ErrChanClosed, got %v", err)
+	}
+}
+
+// TestToChan verifies that a channel receives the value of a resolved promise, and is closed.
+func TestToChan(t *testing.T) {
+	ch := ToChan(Async[int](Square, 5))
+	if val, ok := <-ch; !ok || val != 25 {
+		t.Errorf("Expected 25, got %d (%v)", val, ok)
+	}
+	if _, ok := <-ch; ok {
+		t.Errorf("Expected the channel to be closed")
+	}
+	if _, ok := <-ToChan(Async[int](Fail, 1)); ok {
+		t.Errorf("Expected the channel of a rejected promise to be closed without a value")
+	}
+}
+
+// TestChanArgument verifies that a channel of promises is passed as a channel of values,
+// in the order the promises were sent.
+func TestChanArgument(t *testing.T) {
+	sumChan := func(ch <-chan int) int {
+		sum := 0
+		for n := range ch {
+			sum = sum*10 + n
+		}
+		return sum
+	}
+	ch := make(chan *Promise[int], 3)
+	p := Async[int](sumChan, ch, true)
+	ch <- Async[int](SleepContext, 3)
+	ch <- New(2)
+	ch <- Async[int](SleepContext, 1)
+	close(ch)
+	if val := p.Get(); val != 321 {
+		t.Errorf("Expected 321, got %d", val)
+	}
+
+	failing := make(chan *Promise[int], 3)
+	failing <- New(1)
+	failing <- Async[int](Fail, 1)
+	failing <- New(2)
+	close(failing)
+	var received int
+	record := func(ch <-chan int) int {
+		received = sumChan(ch)
+		return received
+	}
+	if err := Async[int](record, failing, true, WithRetry(RetryPolicy{MaxAttempts: 3})).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the task to be rejected with the error of the element, got %v", err)
+	}
+	if received != 1 {
+		t.Errorf("Expected the channel to be closed after the failed element, got %d", received)
+	}
+}
+
+// TestChanArgumentUndrained verifies that the channel passed to a function that returns without draining it
+// is closed once the task is done, so that feeding it does not block forever.
+func TestChanArgumentUndrained(t *testing.T) {
+	var received <-chan int
+	first := func(ch <-chan int) int {
+		received = ch
+		return <-ch
+	}
+	ch := make(chan *Promise[int])
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case ch <- New(i):
+			case <-time.After(time.Second):
+				return
+			}
+		}
+	}()
+	if val := Async[int](first, ch, true).Get(); val != 1 {
+		t.Errorf("Expected 1, got %d", val)
+	}
+	for range received {
+	}
+}
@@ -0,0 +1,220 @@
+package pas
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// Checkpoint snapshots the results of named tasks to a file, so that a computation
+// interrupted part way can be resumed: when the graph is rebuilt after a restart,
+// tasks whose result is in the checkpoint resolve from it instead of running again.
+// The file is a log to which a record is appended each time a checkpointed task succeeds,
+// so saving a result costs the same however many the file holds. A record torn by a crash is discarded when reopening.
+// A file with the .json extension holds a JSON object per line, any other length-prefixed gob records.
+// Results are decoded into the type of the task restoring them, so no type registration is needed,
+// except for values stored in interfaces, which must be registered with gob.Register.
+type Checkpoint struct {
+	path   string
+	asJSON bool
+
+	mu      sync.Mutex // Guards entries and appending to the file
+	entries map[string]checkpointEntry
+}
+
+// checkpointEntry is the encoded result of a task, with the name of its type
+// to detect results restored by a task of a different type.
+type checkpointEntry struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// checkpointRecord is a record of the checkpoint file: the result of the named task.
+// A later record for the same name overrides an earlier one.
+type checkpointRecord struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// OpenCheckpoint returns a Checkpoint saved to path, loading the results it already holds, if any.
+// Usage example:
+// cp, err := pas.OpenCheckpoint("run.ckpt")
+// p := pas.Async[Model](Train, data, pas.WithName("train"), pas.WithCheckpoint(cp))
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	c := &Checkpoint{
+		path:    path,
+		asJSON:  filepath.Ext(path) == ".json",
+		entries: make(map[string]checkpointEntry),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err == nil {
+		// Drop a torn record at the end, so that the next one is appended after the last complete one
+		if end := c.load(data); end < len(data) {
+			err = os.Truncate(path, int64(end))
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pas: opening checkpoint: %w", err)
+	}
+	return c, nil
+}
+
+// load adds the records held in data to the entries, and returns the length of the complete ones,
+// which is less than that of data if the last record is torn.
+func (c *Checkpoint) load(data []byte) int {
+	end := 0
+	for end < len(data) {
+		var record checkpointRecord
+		var n int
+		if c.asJSON {
+			line := bytes.IndexByte(data[end:], '\n')
+			if line < 0 || json.Unmarshal(data[end:end+line], &record) != nil {
+				return end
+			}
+			n = line + 1
+		} else {
+			size, prefix := binary.Uvarint(data[end:])
+			if prefix <= 0 || size > uint64(len(data)-end-prefix) {
+				return end
+			}
+			n = prefix + int(size)
+			if c.decode(data[end+prefix:end+n], &record) != nil {
+				return end
+			}
+		}
+		c.entries[record.Name] = checkpointEntry{Type: record.Type, Value: record.Value}
+		end += n
+	}
+	return end
+}
+
+// WithCheckpoint restores the task's result from c if it holds one under the task's name,
+// without waiting for its arguments nor calling the function. Otherwise a successful result is saved to c.
+// The task must be named with WithName, and names must be unique among the tasks sharing a Checkpoint.
+// The arguments are not part of the key: a task restored from a checkpoint is assumed to receive the same arguments.
+func WithCheckpoint(c *Checkpoint) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.checkpoint = c
+	})
+}
+
+// Names returns the names of the tasks whose result is in the checkpoint.
+func (c *Checkpoint) Names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, 0, len(c.entries))
+	for name := range c.entries {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Remove deletes the checkpoint file, typically once the computation has completed.
+func (c *Checkpoint) Remove() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]checkpointEntry)
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// encode encodes a value with the Checkpoint's format.
+func (c *Checkpoint) encode(v interface{}) ([]byte, error) {
+	if c.asJSON {
+		return json.Marshal(v)
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+// decode decodes a value encoded with the Checkpoint's format.
+func (c *Checkpoint) decode(data []byte, v interface{}) error {
+	if c.asJSON {
+		return json.Unmarshal(data, v)
+	}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// save records the result of the named task, and appends it to the file.
+func (c *Checkpoint) save(name string, typ reflect.Type, value interface{}) error {
+	data, err := c.encode(value)
+	if err != nil {
+		return err
+	}
+	record, err := c.encode(checkpointRecord{Name: name, Type: typ.String(), Value: data})
+	if err != nil {
+		return err
+	}
+	if c.asJSON {
+		record = append(record, '\n')
+	} else {
+		record = append(binary.AppendUvarint(nil, uint64(len(record))), record...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[name] = checkpointEntry{Type: typ.String(), Value: data}
+	f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(record)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// restore returns the result of the task from its checkpoint, if it holds one.
+func restore[T any](t *task) (output T, ok bool, err error) {
+	c := t.checkpoint
+	if t.name == "" {
+		return output, false, errors.New("pas: WithCheckpoint requires the task to be named with WithName")
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[t.name]
+	c.mu.Unlock()
+	if !ok {
+		return output, false, nil
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if entry.Type != typ.String() {
+		return output, false, fmt.Errorf("pas: checkpoint holds a %s for task %q, not a %s", entry.Type, t.name, typ)
+	}
+	if err := c.decode(entry.Value, &output); err != nil {
+		return output, false, fmt.Errorf("pas: restoring task %q from checkpoint: %w", t.name, err)
+	}
+	t.logger.LogAttrs(context.Background(), slog.LevelDebug, "pas: task restored from checkpoint", LogObserver{}.attrs(t.info())...)
+	return output, true, nil
+}
+
+// checkpointed calls the function and saves its successful result to the task's checkpoint.
+// Failing to save the result is logged, and does not reject the task.
+func checkpointed[T any](t *task, call func() (T, error)) (T, error) {
+	output, err := call()
+	if err == nil {
+		if err := t.checkpoint.save(t.name, reflect.TypeOf((*T)(nil)).Elem(), &output); err != nil {
+			t.logger.LogAttrs(context.Background(), slog.LevelWarn, "pas: failed to save checkpoint",
+				append(LogObserver{}.attrs(t.info()), slog.String("path", t.checkpoint.path), slog.Any("error", err))...)
+		}
+	}
+	return output, err
+}
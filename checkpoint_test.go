@@ -0,0 +1,119 @@
+package pas
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+// TestCheckpointResume verifies that a rebuilt graph resolves completed tasks from the checkpoint
+// and only runs the tasks that did not complete, with both encodings.
+func TestCheckpointResume(t *testing.T) {
+	type stats struct {
+		Sum   int
+		Count int
+	}
+	for _, file := range []string{"run.ckpt", "run.json"} {
+		path := filepath.Join(t.TempDir(), file)
+		var calls atomic.Int32
+		load := func(n int) []int {
+			calls.Add(1)
+			return []int{n, n + 1, n + 2}
+		}
+		summarize := func(nums []int) stats {
+			calls.Add(1)
+			return stats{SumSlice(nums), len(nums)}
+		}
+		crash := true
+		mean := func(s stats) (float64, error) {
+			calls.Add(1)
+			if crash {
+				return 0, errTransient
+			}
+			return float64(s.Sum) / float64(s.Count), nil
+		}
+		run := func() *Promise[float64] {
+			cp, err := OpenCheckpoint(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nums := Async[[]int](load, 1, WithName("load"), WithCheckpoint(cp))
+			s := Async[stats](summarize, nums, WithName("summarize"), WithCheckpoint(cp))
+			return Async[float64](mean, s, WithName("mean"), WithCheckpoint(cp))
+		}
+
+		if err := run().Err(); !errors.Is(err, errTransient) {
+			t.Fatalf("%s: expected the first run to fail, got %v", file, err)
+		}
+		crash = false
+		calls.Store(0)
+		if val, err := run().Result(); err != nil || val != 2 {
+			t.Errorf("%s: expected 2, got %v (%v)", file, val, err)
+		}
+		if n := calls.Load(); n != 1 {
+			t.Errorf("%s: expected only the failed task to run again, got %d calls", file, n)
+		}
+	}
+}
+
+// TestCheckpointRequiresName verifies that unnamed or mistyped tasks are rejected.
+func TestCheckpointRequiresName(t *testing.T) {
+	cp, err := OpenCheckpoint(filepath.Join(t.TempDir(), "run.ckpt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Async[int](Square, 2, WithCheckpoint(cp)).Err(); err == nil {
+		t.Errorf("Expected an unnamed task to be rejected")
+	}
+	Sync[int](Square, 2, WithName("square"), WithCheckpoint(cp))
+	if err := Async[string](func() string { return "" }, WithName("square"), WithCheckpoint(cp)).Err(); err == nil {
+		t.Errorf("Expected a task of a different type to be rejected")
+	}
+	if err := cp.Remove(); err != nil || len(cp.Names()) != 0 {
+		t.Errorf("Expected an empty checkpoint, got %v (%v)", cp.Names(), err)
+	}
+}
+
+// TestCheckpointLog verifies that results are appended to the file, and that a record torn by a crash
+// is discarded when reopening, with both encodings.
+func TestCheckpointLog(t *testing.T) {
+	for _, file := range []string{"run.ckpt", "run.json"} {
+		path := filepath.Join(t.TempDir(), file)
+		cp, err := OpenCheckpoint(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Sync[int](Square, 2, WithName("a"), WithCheckpoint(cp))
+		before, _ := os.ReadFile(path)
+		Sync[int](Square, 3, WithName("b"), WithCheckpoint(cp))
+		after, _ := os.ReadFile(path)
+		if len(before) == 0 || !bytes.HasPrefix(after, before) {
+			t.Errorf("%s: expected a record to be appended, got %q then %q", file, before, after)
+		}
+
+		// A crash while appending leaves part of a record
+		if err := os.WriteFile(path, append(after, after[len(before):len(after)-2]...), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if cp, err = OpenCheckpoint(path); err != nil {
+			t.Fatal(err)
+		}
+		Sync[int](Square, 4, WithName("c"), WithCheckpoint(cp))
+		if cp, err = OpenCheckpoint(path); err != nil {
+			t.Fatal(err)
+		}
+		names := cp.Names()
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+			t.Errorf("%s: expected [a b c], got %v", file, names)
+		}
+		if val := Sync[int](Fail, 1, WithName("c"), WithCheckpoint(cp)); val != 16 {
+			t.Errorf("%s: expected 16 restored, got %d", file, val)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package pas
+
+import "reflect"
+
+// Indexed is the result of the Promise at Index in the slice passed to AsCompleted.
+// Err is the error the Promise was rejected with, or nil if it was resolved with Value.
+type Indexed[T any] struct {
+	Index int
+	Value T
+	Err   error
+}
+
+// AsCompleted returns a channel yielding the result of each Promise of ps in the order they are settled,
+// and closed once all of them are. The channel is buffered for all the results,
+// so a consumer may stop reading early without leaking the goroutine feeding it.
+// The Promises are waited on by one goroutine per batch of 128, whatever their number.
+// Usage example: for r := range pas.AsCompleted(pages) { handle(r.Index, r.Value, r.Err) }
+func AsCompleted[T any](ps []*Promise[T]) <-chan Indexed[T] {
+	out := make(chan Indexed[T], len(ps))
+	go func() {
+		defer close(out)
+		waitAll(ps, func(i int) {
+			p := ps[i]
+			out <- Indexed[T]{Index: i, Value: p.value, Err: p.err}
+		})
+	}()
+	return out
+}
+
+// AsCompletedFunc calls f with the result of each Promise of ps in the order they are settled,
+// and returns once all of them are. Calls of f are sequential, on the calling goroutine.
+func AsCompletedFunc[T any](ps []*Promise[T], f func(r Indexed[T])) {
+	waitAll(ps, func(i int) {
+		p := ps[i]
+		f(Indexed[T]{Index: i, Value: p.value, Err: p.err})
+	})
+}
+
+// watchBatch is the number of Promises each goroutine of waitAll selects on.
+// It bounds the cost of each select, which grows with the number of cases, and stays far below the limit of reflect.Select.
+const watchBatch = 128
+
+// waitAll calls settled with the index of each Promise of ps as it is settled, in that order.
+// The pending Promises are split into batches, each watched by a goroutine reporting to the caller's goroutine.
+func waitAll[T any](ps []*Promise[T], settled func(i int)) {
+	var pending []int
+	for i, p := range ps {
+		select {
+		case <-p.ready:
+			settled(i)
+		default:
+			pending = append(pending, i)
+		}
+	}
+	done := make(chan int, len(pending))
+	for lo := 0; lo < len(pending); lo += watchBatch {
+		go watchPromises(ps, pending[lo:min(lo+watchBatch, len(pending))], done)
+	}
+	for range pending {
+		settled(<-done)
+	}
+}
+
+// watchPromises sends to done the index of each Promise of ps among indices as it is settled.
+// It selects on the ready channels of all of them at once.
+func watchPromises[T any](ps []*Promise[T], indices []int, done chan<- int) {
+	indices = append([]int(nil), indices...)
+	cases := make([]reflect.SelectCase, len(indices))
+	for j, i := range indices {
+		cases[j] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ps[i].ready)}
+	}
+	for len(cases) > 0 {
+		chosen, _, _ := reflect.Select(cases)
+		done <- indices[chosen]
+
+		// Remove the case by moving the last one in its place
+		last := len(cases) - 1
+		cases[chosen], indices[chosen] = cases[last], indices[last]
+		cases, indices = cases[:last], indices[:last]
+	}
+}
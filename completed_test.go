@@ -0,0 +1,68 @@
+package pas
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestAsCompleted verifies that results are yielded in completion order, with failures.
+func TestAsCompleted(t *testing.T) {
+	ps := []*Promise[int]{
+		Async[int](SleepContext, 60),
+		Async[int](SleepContext, 20),
+		Async[int](Fail, 1),
+		New(5),
+		Async[int](SleepContext, 40),
+	}
+	var order []int
+	for r := range AsCompleted(ps) {
+		order = append(order, r.Index)
+		if r.Index == 2 && !errors.Is(r.Err, errTransient) {
+			t.Errorf("Expected the failure of promise 2, got %v", r.Err)
+		}
+		if r.Index == 4 && r.Value != 40 {
+			t.Errorf("Expected 40 for promise 4, got %d", r.Value)
+		}
+	}
+	if len(order) != 5 || order[2] != 1 || order[3] != 4 || order[4] != 0 {
+		t.Errorf("Expected the slow promises last in completion order, got %v", order)
+	}
+}
+
+// TestAsCompletedFunc verifies the callback variant.
+func TestAsCompletedFunc(t *testing.T) {
+	ps := []*Promise[int]{Async[int](SleepContext, 30), Async[int](SleepContext, 1)}
+	var order []int
+	AsCompletedFunc(ps, func(r Indexed[int]) {
+		order = append(order, r.Index)
+	})
+	if len(order) != 2 || order[0] != 1 {
+		t.Errorf("Expected [1 0], got %v", order)
+	}
+	AsCompletedFunc(nil, func(Indexed[int]) { t.Errorf("Expected no calls") })
+}
+
+// TestAsCompletedMany verifies that more Promises than a select can handle at once are all yielded.
+func TestAsCompletedMany(t *testing.T) {
+	ps := make([]*Promise[int], 70000)
+	for i := range ps {
+		ps[i] = newPending[int]()
+	}
+	go func() {
+		for i := len(ps) - 1; i >= 0; i-- {
+			ps[i].settle(i, nil)
+		}
+	}()
+	seen := make([]bool, len(ps))
+	for r := range AsCompleted(ps) {
+		if r.Value != r.Index || seen[r.Index] {
+			t.Fatalf("Expected each promise once with its index, got %+v", r)
+		}
+		seen[r.Index] = true
+	}
+	for i, ok := range seen {
+		if !ok {
+			t.Fatalf("Expected promise %d to be yielded", i)
+		}
+	}
+}
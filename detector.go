@@ -0,0 +1,247 @@
+package pas
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// activeDetector is the Detector currently tracking pending promises, or nil.
+var activeDetector atomic.Pointer[Detector]
+
+// Detector tracks every pending promise with its creation site and blocked waiters,
+// to find out what a hanging program is stuck on.
+// Only tasks created while the Detector is active are tracked.
+type Detector struct {
+	out      io.Writer
+	interval time.Duration
+	progress atomic.Uint64
+	stop     chan struct{}
+
+	mu      sync.Mutex
+	pending map[uint64]*pendingTask
+}
+
+// pendingTask is the state of a tracked task whose result is not yet available.
+type pendingTask struct {
+	task      *task
+	state     string
+	waitingOn uint64         // The promise the task is blocked on, or 0
+	getters   map[string]int // Goroutines blocked in Get on the task's promise, by call site
+}
+
+// StartDetector creates a Detector and makes it the active one.
+// If no promise has made progress for the given interval while some are pending,
+// the Detector writes a report to w, or to standard error if w is nil.
+// An interval of 0 disables the automatic reports; call Report instead.
+// Only one Detector is active at a time; starting a new one replaces the previous one.
+// Usage example:
+// d := pas.StartDetector(10*time.Second, nil)
+// defer d.Stop()
+func StartDetector(interval time.Duration, w io.Writer) *Detector {
+	if w == nil {
+		w = os.Stderr
+	}
+	d := &Detector{
+		out:      w,
+		interval: interval,
+		stop:     make(chan struct{}),
+		pending:  make(map[uint64]*pendingTask),
+	}
+	if old := activeDetector.Swap(d); old != nil {
+		old.Stop()
+	}
+	if interval > 0 {
+		go d.watch()
+	}
+	return d
+}
+
+// Stop stops tracking and automatic reports.
+func (d *Detector) Stop() {
+	activeDetector.CompareAndSwap(d, nil)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+}
+
+// watch writes a report whenever no progress was made for an interval.
+// It reports each stall once.
+func (d *Detector) watch() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	last, reported := d.progress.Load(), false
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+		current := d.progress.Load()
+		if current != last {
+			last, reported = current, false
+			continue
+		}
+		d.mu.Lock()
+		stuck := len(d.pending) > 0
+		d.mu.Unlock()
+		if stuck && !reported {
+			var b strings.Builder
+			fmt.Fprintf(&b, "pas: no progress for %v\n", d.interval)
+			d.WriteReport(&b)
+			io.WriteString(d.out, b.String())
+			reported = true
+		}
+	}
+}
+
+// track starts tracking a new task.
+func (d *Detector) track(t *task) {
+	d.mu.Lock()
+	d.pending[t.id] = &pendingTask{task: t, state: "waiting for arguments"}
+	d.mu.Unlock()
+	d.progress.Add(1)
+}
+
+// started records that the task's function is running.
+func (d *Detector) started(id uint64) {
+	d.mu.Lock()
+	if pt, ok := d.pending[id]; ok {
+		pt.state = "running"
+	}
+	d.mu.Unlock()
+	d.progress.Add(1)
+}
+
+// finished records the end of a task.
+// Sync tasks are no longer pending; Async tasks are pending until their promise is settled.
+func (d *Detector) finished(t *task) {
+	if t.sync {
+		d.mu.Lock()
+		delete(d.pending, t.id)
+		d.mu.Unlock()
+	}
+	d.progress.Add(1)
+}
+
+// settled stops tracking the task producing the given promise.
+func (d *Detector) settled(id uint64) {
+	d.mu.Lock()
+	delete(d.pending, id)
+	d.mu.Unlock()
+	d.progress.Add(1)
+}
+
+// taskBlocked records that a task waits for a promise, and returns the function to call once it is ready.
+func (d *Detector) taskBlocked(id, on uint64) func() {
+	d.mu.Lock()
+	if pt, ok := d.pending[id]; ok {
+		pt.waitingOn = on
+	}
+	d.mu.Unlock()
+	return func() {
+		d.mu.Lock()
+		if pt, ok := d.pending[id]; ok {
+			pt.waitingOn = 0
+		}
+		d.mu.Unlock()
+		d.progress.Add(1)
+	}
+}
+
+// getterBlocked records that a goroutine waits in Get for a promise, and returns the function to call once it is ready.
+func (d *Detector) getterBlocked(on uint64, site string) func() {
+	d.mu.Lock()
+	if pt, ok := d.pending[on]; ok {
+		if pt.getters == nil {
+			pt.getters = make(map[string]int)
+		}
+		pt.getters[site]++
+	}
+	d.mu.Unlock()
+	return func() {
+		d.mu.Lock()
+		if pt, ok := d.pending[on]; ok {
+			pt.getters[site]--
+			if pt.getters[site] == 0 {
+				delete(pt.getters, site)
+			}
+		}
+		d.mu.Unlock()
+	}
+}
+
+// Report writes the pending dependency chains to the Detector's writer.
+func (d *Detector) Report() {
+	d.WriteReport(d.out)
+}
+
+// WriteReport writes the pending dependency chains to w.
+// Pending tasks are grouped by the root promise they are ultimately stuck on:
+// a promise whose task is not itself waiting for another promise.
+func (d *Detector) WriteReport(w io.Writer) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "pas: %d pending tasks\n", len(d.pending))
+
+	// Find the root of each pending task, and the direct waiters of each promise
+	waiters := make(map[uint64][]uint64)
+	roots := make(map[uint64]bool)
+	for id, pt := range d.pending {
+		if pt.waitingOn != 0 {
+			waiters[pt.waitingOn] = append(waiters[pt.waitingOn], id)
+		}
+		root, seen := id, map[uint64]bool{}
+		for pt := d.pending[root]; pt != nil && pt.waitingOn != 0 && !seen[root]; pt = d.pending[root] {
+			seen[root] = true
+			root = pt.waitingOn
+		}
+		roots[root] = true
+	}
+	sortedRoots := make([]uint64, 0, len(roots))
+	for id := range roots {
+		sortedRoots = append(sortedRoots, id)
+	}
+	sort.Slice(sortedRoots, func(i, j int) bool { return sortedRoots[i] < sortedRoots[j] })
+
+	var write func(id uint64, depth int)
+	write = func(id uint64, depth int) {
+		indent := strings.Repeat("  ", depth)
+		if pt, ok := d.pending[id]; ok {
+			fmt.Fprintf(&b, "%s#%d %s: %s\n", indent, id, pt.task.describe(), pt.state)
+			sites := make([]string, 0, len(pt.getters))
+			for site := range pt.getters {
+				sites = append(sites, site)
+			}
+			sort.Strings(sites)
+			for _, site := range sites {
+				fmt.Fprintf(&b, "%s  <- %d goroutine(s) blocked in Get at %s\n", indent, pt.getters[site], filepath.Base(site))
+			}
+		} else {
+			fmt.Fprintf(&b, "%s#%d: untracked promise\n", indent, id)
+		}
+		children := waiters[id]
+		sort.Slice(children, func(i, j int) bool { return children[i] < children[j] })
+		for _, child := range children {
+			write(child, depth+1)
+		}
+	}
+	for _, root := range sortedRoots {
+		fmt.Fprintf(&b, "stuck on #%d:\n", root)
+		write(root, 1)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
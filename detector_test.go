@@ -0,0 +1,84 @@
+package pas
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestDetectorFindsRoot verifies that the Detector reports the chain of pending tasks
+// stuck behind a task that never returns, including goroutines blocked in Get.
+func TestDetectorFindsRoot(t *testing.T) {
+	var out lockedBuffer
+	d := StartDetector(20*time.Millisecond, &out)
+	defer d.Stop()
+
+	release := make(chan struct{})
+	defer close(release)
+	block := func(n int) int {
+		<-release
+		return n
+	}
+	root := Async[int](block, 1, WithName("root"))
+	middle := Async[int](Add, root, 1)
+	leaf := Async[int](Square, middle, WithName("leaf"))
+	go func() { leaf.Get() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), "no progress") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	report := out.String()
+	for _, want := range []string{
+		fmt.Sprintf("stuck on #%d:", root.id),
+		`"root"`, "running",
+		fmt.Sprintf("#%d", middle.id), "waiting for arguments",
+		`"leaf"`, "blocked in Get at detector_test.go:",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+	if strings.Count(report, "stuck on") != 1 {
+		t.Errorf("Expected a single root, got:\n%s", report)
+	}
+}
+
+// TestDetectorReportAfterCompletion verifies that completed tasks are no longer reported.
+func TestDetectorReportAfterCompletion(t *testing.T) {
+	d := StartDetector(0, nil)
+	defer d.Stop()
+
+	p := Async[int](Add, Async[int](Square, 2), 1)
+	p.Get()
+	Sync[int](Square, p)
+
+	var out bytes.Buffer
+	if err := d.WriteReport(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "pas: 0 pending tasks") {
+		t.Errorf("Expected no pending tasks, got:\n%s", out.String())
+	}
+}
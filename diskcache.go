@@ -0,0 +1,139 @@
+package pas
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+)
+
+// DiskCache persists the results of tasks in a local directory, so that they survive restarts.
+// Each result is stored in its own gob-encoded file, named by a hash of the function symbol,
+// the version given to WithDiskCache, and the gob-encoded resolved arguments.
+// Maps are encoded in random order, so calls with map arguments are not reliably found in the cache.
+// Values stored in interfaces must be registered with gob.Register.
+type DiskCache struct {
+	dir    string
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// OpenDiskCache returns a DiskCache storing its entries in dir, creating the directory if needed.
+// Usage example:
+// cache, err := pas.OpenDiskCache(".cache/pas")
+// p := pas.Async[Report](Analyze, input, pas.WithDiskCache(cache, "v2"))
+func OpenDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("pas: opening disk cache: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// WithDiskCache loads the task's result from c if it was stored by an earlier call with
+// the same function, version and resolved arguments, instead of calling the function.
+// Otherwise a successful result is stored in c once the function returns.
+// Change the version whenever the function's behavior changes, to invalidate its old entries.
+// Functions are identified by their symbol, which for closures depends on their position in the enclosing function.
+func WithDiskCache(c *DiskCache, version string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.diskCache = c
+		cfg.diskCacheVersion = version
+	})
+}
+
+// Stats returns the number of calls that were loaded from the cache, and that called their function.
+func (c *DiskCache) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// Clear removes all entries from the cache.
+func (c *DiskCache) Clear() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".gob" {
+			if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// path returns the file storing the result of the task's call with the given resolved arguments.
+func (c *DiskCache) path(t *task, version string, args []reflect.Value) (string, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for i, arg := range args {
+		if err := enc.EncodeValue(arg); err != nil {
+			return "", fmt.Errorf("pas: encoding argument %d for the disk cache: %w", i, err)
+		}
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", t.fn, version)
+	h.Write(buf.Bytes())
+	return filepath.Join(c.dir, hex.EncodeToString(h.Sum(nil))+".gob"), nil
+}
+
+// store writes an entry atomically, so that a crash never leaves a partial entry behind.
+func (c *DiskCache) store(path string, value interface{}) error {
+	f, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := gob.NewEncoder(f).Encode(value); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// loadOrCall returns the result of the task's call with the given resolved arguments from the disk cache,
+// or calls the function and stores its result.
+// Failing to store the result is logged, and does not reject the task.
+func loadOrCall[T any](t *task, args []reflect.Value, call func() (T, error)) (output T, err error) {
+	c := t.diskCache
+	path, err := c.path(t, t.diskCacheVersion, args)
+	if err != nil {
+		return output, err
+	}
+
+	if f, err := os.Open(path); err == nil {
+		var cached T
+		err = gob.NewDecoder(f).Decode(&cached)
+		f.Close()
+		if err == nil {
+			c.hits.Add(1)
+			return cached, nil
+		}
+		t.logger.LogAttrs(context.Background(), slog.LevelWarn, "pas: ignoring corrupt disk cache entry",
+			append(LogObserver{}.attrs(t.info()), slog.String("path", path), slog.Any("error", err))...)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return output, fmt.Errorf("pas: reading disk cache: %w", err)
+	}
+
+	c.misses.Add(1)
+	output, err = call()
+	if err == nil {
+		if err := c.store(path, &output); err != nil {
+			t.logger.LogAttrs(context.Background(), slog.LevelWarn, "pas: failed to store result in disk cache",
+				append(LogObserver{}.attrs(t.info()), slog.String("path", path), slog.Any("error", err))...)
+		}
+	}
+	return output, err
+}
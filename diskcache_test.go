@@ -0,0 +1,73 @@
+package pas
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// TestDiskCache verifies that results are loaded from disk by a new cache on the same directory,
+// and that changing the version invalidates them.
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	var calls atomic.Int32
+	concat := func(words []string, sep string) string {
+		calls.Add(1)
+		out := ""
+		for i, w := range words {
+			if i > 0 {
+				out += sep
+			}
+			out += w
+		}
+		return out
+	}
+
+	cache, err := OpenDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := Async[[]string](func() []string { return []string{"a", "b"} })
+	if val := Async[string](concat, words, "-", WithDiskCache(cache, "v1")).Get(); val != "a-b" {
+		t.Errorf("Expected a-b, got %q", val)
+	}
+
+	// A new cache on the same directory, as after a restart
+	cache, err = OpenDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val := Sync[string](concat, []string{"a", "b"}, "-", WithDiskCache(cache, "v1")); val != "a-b" {
+		t.Errorf("Expected the cached a-b, got %q", val)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected 1 call, got %d", n)
+	}
+	Sync[string](concat, []string{"a", "b"}, "+", WithDiskCache(cache, "v1"))
+	Sync[string](concat, []string{"a", "b"}, "-", WithDiskCache(cache, "v2"))
+	if hits, misses := cache.Stats(); hits != 1 || misses != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %d and %d", hits, misses)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	Sync[string](concat, []string{"a", "b"}, "-", WithDiskCache(cache, "v1"))
+	if n := calls.Load(); n != 4 {
+		t.Errorf("Expected the cleared entry to be recomputed, got %d calls", n)
+	}
+}
+
+// TestDiskCacheSkipsFailures verifies that failed results are not stored.
+func TestDiskCacheSkipsFailures(t *testing.T) {
+	cache, err := OpenDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls atomic.Int32
+	if err := Async[int](flaky(1, &calls), 3, WithDiskCache(cache, "")).Err(); err == nil {
+		t.Errorf("Expected the first call to fail")
+	}
+	if val := Async[int](flaky(1, &calls), 3, WithDiskCache(cache, "")).Get(); val != 6 {
+		t.Errorf("Expected 6, got %d", val)
+	}
+}
@@ -0,0 +1,179 @@
+package pas
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// Executor bounds the number of tasks running at once. Tasks are assigned to an Executor with WithExecutor;
+// once their arguments are resolved, they wait in its queue for one of its workers to be free.
+// The queue is ordered by priority, set with WithPriority, then by arrival.
+// A queued task whose promise is awaited by a task of higher priority inherits that priority,
+// transitively through the tasks it is itself waiting on, so that urgent work is not held up behind background work.
+// Each attempt of a task holds a worker while it runs, until its function returns even past its WithTimeout budget;
+// waiting for arguments and retry backoff do not.
+// Tasks calling Sync on the same Executor from within their function may deadlock it once all its workers are busy.
+// Usage example:
+// e := pas.NewExecutor(runtime.GOMAXPROCS(0))
+// p := pas.Async[Page](Render, req, pas.WithExecutor(e), pas.WithPriority(10))
+type Executor struct {
+	workers int
+
+	mu      sync.Mutex
+	running int
+	queue   execQueue
+	queued  map[*task]*execItem
+	seq     uint64
+}
+
+// schedState is the scheduling state of a task, shared with the copies of the task made by Hedge.
+type schedState struct {
+	mu         sync.Mutex
+	priority   int
+	waitingFor *task // The task producing the promise the task is blocked on, or nil
+}
+
+// execItem is a task waiting in the queue of an Executor.
+type execItem struct {
+	task     *task
+	priority int
+	seq      uint64
+	index    int
+	ready    chan struct{}
+}
+
+// execQueue is a heap of execItems, by decreasing priority then increasing arrival.
+type execQueue []*execItem
+
+func (q execQueue) Len() int { return len(q) }
+
+func (q execQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q execQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+
+func (q *execQueue) Push(x interface{}) {
+	item := x.(*execItem)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+func (q *execQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// NewExecutor creates an Executor running at most workers tasks at once.
+func NewExecutor(workers int) *Executor {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Executor{workers: workers, queued: make(map[*task]*execItem)}
+}
+
+// WithExecutor runs the task on e, which bounds the number of tasks running at once.
+func WithExecutor(e *Executor) Option {
+	return optionFunc(func(c *config) {
+		c.executor = e
+	})
+}
+
+// WithPriority sets the priority of the task in the queue of its Executor. Higher priorities run first;
+// the default is 0. The task's priority is raised while a task of higher priority waits on its promise.
+func WithPriority(n int) Option {
+	return optionFunc(func(c *config) {
+		c.priority = n
+	})
+}
+
+// Queued returns the number of tasks waiting for a worker.
+func (e *Executor) Queued() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.queue)
+}
+
+// acquire waits for a worker to be free for the task, and returns the function releasing it.
+func (e *Executor) acquire(t *task) func() {
+	e.mu.Lock()
+	if e.running < e.workers {
+		e.running++
+		e.mu.Unlock()
+		return e.release
+	}
+	e.seq++
+	item := &execItem{task: t, priority: t.sched.get(), seq: e.seq, ready: make(chan struct{})}
+	heap.Push(&e.queue, item)
+	e.queued[t] = item
+	e.mu.Unlock()
+
+	<-item.ready
+	return e.release
+}
+
+// release hands the worker of a finished task over to the first queued task, if any.
+func (e *Executor) release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.queue) == 0 {
+		e.running--
+		return
+	}
+	item := heap.Pop(&e.queue).(*execItem)
+	delete(e.queued, item.task)
+	close(item.ready)
+}
+
+// reprioritize moves a queued task according to its new priority.
+func (e *Executor) reprioritize(t *task, priority int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if item, ok := e.queued[t]; ok && item.priority < priority {
+		item.priority = priority
+		heap.Fix(&e.queue, item.index)
+	}
+}
+
+// get returns the current priority of the task.
+func (s *schedState) get() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.priority
+}
+
+// waitFor records the task producing the promise the task is blocked on, or nil once it is ready.
+func (s *schedState) waitFor(producer *task) {
+	s.mu.Lock()
+	s.waitingFor = producer
+	s.mu.Unlock()
+}
+
+// inheritPriority raises the priority of t to at least priority, and that of the tasks it is waiting on,
+// moving them forward in the queue of their Executor.
+func (t *task) inheritPriority(priority int) {
+	for t != nil {
+		s := t.sched
+		s.mu.Lock()
+		if s.priority >= priority {
+			s.mu.Unlock()
+			return
+		}
+		s.priority = priority
+		next := s.waitingFor
+		s.mu.Unlock()
+
+		if t.executor != nil {
+			t.executor.reprioritize(t, priority)
+		}
+		t = next
+	}
+}
@@ -0,0 +1,93 @@
+package pas
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockExecutor occupies the only worker of e until the returned function is called.
+func blockExecutor(t *testing.T, e *Executor) func() {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	Async[int](func() int {
+		close(started)
+		<-release
+		return 0
+	}, WithExecutor(e))
+	<-started
+	return func() { close(release) }
+}
+
+// waitQueued waits until n tasks are queued on e.
+func waitQueued(t *testing.T, e *Executor, n int) {
+	deadline := time.Now().Add(time.Second)
+	for e.Queued() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d queued tasks, got %d", n, e.Queued())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestExecutorPriority verifies that queued tasks run by priority, then by arrival.
+func TestExecutorPriority(t *testing.T) {
+	e := NewExecutor(1)
+	unblock := blockExecutor(t, e)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+		return name
+	}
+	a := Async[string](record, "a", WithExecutor(e))
+	waitQueued(t, e, 1)
+	b := Async[string](record, "b", WithExecutor(e))
+	waitQueued(t, e, 2)
+	c := Async[string](record, "c", WithExecutor(e), WithPriority(10))
+	waitQueued(t, e, 3)
+	unblock()
+
+	a.Get()
+	b.Get()
+	c.Get()
+	if len(order) != 3 || order[0] != "c" || order[1] != "a" || order[2] != "b" {
+		t.Errorf("Expected [c a b], got %v", order)
+	}
+}
+
+// TestExecutorPriorityInheritance verifies that a queued task awaited by a task of higher priority
+// is moved ahead of the other queued tasks.
+func TestExecutorPriorityInheritance(t *testing.T) {
+	e := NewExecutor(1)
+	unblock := blockExecutor(t, e)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+		return name
+	}
+	background := Async[string](record, "background", WithExecutor(e), WithPriority(1))
+	waitQueued(t, e, 1)
+	needed := Async[string](record, "needed", WithExecutor(e))
+	waitQueued(t, e, 2)
+	// The urgent task does not use the executor, and waits on needed through another task
+	exclaim := Async[string](func(s string) string { return s + "!" }, needed)
+	urgent := Async[string](func(s string) string { return s }, exclaim, WithPriority(5))
+	time.Sleep(10 * time.Millisecond)
+	unblock()
+
+	if val := urgent.Get(); val != "needed!" {
+		t.Errorf("Expected needed!, got %q", val)
+	}
+	background.Get()
+	if len(order) != 2 || order[0] != "needed" {
+		t.Errorf("Expected the awaited task to run first, got %v", order)
+	}
+}
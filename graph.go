@@ -0,0 +1,193 @@
+package pas
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// activeRecorder is the Recorder currently capturing the dependency graph, or nil.
+var activeRecorder atomic.Pointer[Recorder]
+
+// Node is a single Async or Sync invocation in a recorded dependency graph.
+// Nodes with an empty Func stand for promises that were not produced by a function,
+// such as those created by New or MakeSlice.
+type Node struct {
+	ID           uint64
+	Name         string // Name set by WithName, or empty
+	Site         string // File and line where Async or Sync was called
+	Func         string // Function symbol, as reported by runtime.FuncForPC
+	NumArgs      int
+	Sync         bool
+	Created      time.Time
+	ArgsResolved time.Time
+	Started      time.Time
+	Finished     time.Time
+}
+
+// Edge records that the promise From was passed, directly or nested, to the invocation To.
+type Edge struct {
+	From uint64
+	To   uint64
+}
+
+// Graph is a snapshot of the dependency graph captured by a Recorder.
+// Nodes are sorted by ID, and edges by (From, To).
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Recorder captures the dependency graph built by Async and Sync calls.
+type Recorder struct {
+	mu    sync.Mutex
+	nodes map[uint64]Node
+	edges map[Edge]struct{}
+}
+
+// StartRecording creates a Recorder and makes it the active one.
+// Only one Recorder is active at a time; starting a new recording replaces the previous one.
+// Usage example:
+// rec := pas.StartRecording()
+// defer rec.Stop()
+func StartRecording() *Recorder {
+	r := &Recorder{
+		nodes: make(map[uint64]Node),
+		edges: make(map[Edge]struct{}),
+	}
+	activeRecorder.Store(r)
+	return r
+}
+
+// Stop stops the Recorder from capturing further nodes and edges.
+// The graph captured so far remains available.
+func (r *Recorder) Stop() {
+	activeRecorder.CompareAndSwap(r, nil)
+}
+
+// addNode records a finished task.
+func (r *Recorder) addNode(t *task) {
+	n := t.node()
+	r.mu.Lock()
+	r.nodes[n.ID] = n
+	r.mu.Unlock()
+}
+
+// addEdge records that promise from is a dependency of task to.
+func (r *Recorder) addEdge(from, to uint64) {
+	r.mu.Lock()
+	r.edges[Edge{From: from, To: to}] = struct{}{}
+	r.mu.Unlock()
+}
+
+// Graph returns a snapshot of the nodes and edges captured so far.
+// Promises that appear only as the source of an edge are included as nodes with an empty Func.
+func (r *Recorder) Graph() Graph {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	nodes := make(map[uint64]Node, len(r.nodes))
+	for id, n := range r.nodes {
+		nodes[id] = n
+	}
+	g := Graph{Edges: make([]Edge, 0, len(r.edges))}
+	for e := range r.edges {
+		g.Edges = append(g.Edges, e)
+		for _, id := range []uint64{e.From, e.To} {
+			if _, ok := nodes[id]; !ok {
+				nodes[id] = Node{ID: id}
+			}
+		}
+	}
+	g.Nodes = make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// WriteDOT writes the captured graph to w in Graphviz DOT format.
+func (r *Recorder) WriteDOT(w io.Writer) error {
+	g := r.Graph()
+	var b strings.Builder
+	b.WriteString("digraph pas {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		label := strings.ReplaceAll(n.label(), `"`, `\"`)
+		if n.Func == "" {
+			fmt.Fprintf(&b, "\tn%d [label=\"%s\", shape=ellipse];\n", n.ID, label)
+		} else {
+			fmt.Fprintf(&b, "\tn%d [label=\"%s\"];\n", n.ID, strings.ReplaceAll(label, "\n", `\n`))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\tn%d -> n%d;\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the captured graph to w as a Mermaid flowchart.
+func (r *Recorder) WriteMermaid(w io.Writer) error {
+	g := r.Graph()
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		label := strings.ReplaceAll(n.label(), `"`, "#quot;")
+		if n.Func == "" {
+			fmt.Fprintf(&b, "\tn%d([\"%s\"])\n", n.ID, label)
+		} else {
+			fmt.Fprintf(&b, "\tn%d[\"%s\"]\n", n.ID, strings.ReplaceAll(label, "\n", "<br/>"))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\tn%d --> n%d\n", e.From, e.To)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// label returns a human-readable description of the node, used by the exporters.
+func (n Node) label() string {
+	if n.Func == "" {
+		return fmt.Sprintf("#%d", n.ID)
+	}
+	kind := "Async"
+	if n.Sync {
+		kind = "Sync"
+	}
+	run := "not started"
+	if !n.Started.IsZero() {
+		run = n.Finished.Sub(n.Started).Round(time.Microsecond).String()
+	}
+	return fmt.Sprintf("%s #%d\n%s at %s\n%d args, %s", n.shortName(), n.ID, kind, filepath.Base(n.Site), n.NumArgs, run)
+}
+
+// shortName returns the node's name if set, or its function symbol without its package path.
+func (n Node) shortName() string {
+	if n.Name != "" {
+		return n.Name
+	}
+	if n.Func == "" {
+		return fmt.Sprintf("#%d", n.ID)
+	}
+	name := n.Func
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
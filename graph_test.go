@@ -0,0 +1,75 @@
+package pas
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRecorderCapturesEdges verifies that the Recorder captures top-level and nested dependencies.
+func TestRecorderCapturesEdges(t *testing.T) {
+	rec := StartRecording()
+	defer rec.Stop()
+
+	a := Async[int](Square, 2)
+	b := Async[int](Square, 3)
+	sum := Async[int](Add, a, b)
+	arr := []*Promise[int]{a, sum}
+	total := Sync[int](SumSlice, arr, true)
+	if total != 17 {
+		t.Fatalf("Expected 17, got %d", total)
+	}
+	rec.Stop()
+
+	g := rec.Graph()
+	expected := []Edge{{a.id, sum.id}, {b.id, sum.id}}
+	has := make(map[Edge]bool)
+	for _, e := range g.Edges {
+		has[e] = true
+	}
+	for _, e := range expected {
+		if !has[e] {
+			t.Errorf("Expected edge %d -> %d in %v", e.From, e.To, g.Edges)
+		}
+	}
+	// The Sync call depends on a and sum through the slice
+	var syncID uint64
+	for _, n := range g.Nodes {
+		if n.Sync {
+			syncID = n.ID
+		}
+	}
+	if syncID == 0 || !has[Edge{a.id, syncID}] || !has[Edge{sum.id, syncID}] {
+		t.Errorf("Expected nested edges into Sync node, got %v", g.Edges)
+	}
+	for _, n := range g.Nodes {
+		if n.ID == sum.id && (!strings.HasSuffix(n.Func, ".Add") || n.NumArgs != 2) {
+			t.Errorf("Expected node for Add with 2 args, got %+v", n)
+		}
+	}
+}
+
+// TestRecorderExport verifies the DOT and Mermaid exports of a recorded graph.
+func TestRecorderExport(t *testing.T) {
+	rec := StartRecording()
+	p := Async[int](Add, New(1), 2)
+	p.Get()
+	Sync[int](Square, p)
+	rec.Stop()
+
+	var dot, mermaid strings.Builder
+	if err := rec.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.WriteMermaid(&mermaid); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dot.String(), "digraph pas {") || !strings.Contains(dot.String(), "->") {
+		t.Errorf("Unexpected DOT output:\n%s", dot.String())
+	}
+	if !strings.HasPrefix(mermaid.String(), "flowchart LR") || !strings.Contains(mermaid.String(), "-->") {
+		t.Errorf("Unexpected Mermaid output:\n%s", mermaid.String())
+	}
+	if !strings.Contains(dot.String(), "pas.Add") || !strings.Contains(mermaid.String(), "pas.Square") {
+		t.Errorf("Expected function names in exports:\nDOT:\n%s\nMermaid:\n%s", dot.String(), mermaid.String())
+	}
+}
@@ -0,0 +1,148 @@
+package pas
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// Hedge is like Async, but reduces tail latency by speculative execution:
+// if f has not returned after the given delay, a second copy of f is started with the same resolved arguments,
+// and the Promise is resolved by whichever copy succeeds first.
+// The context of the other copy is then cancelled, so f should take a context.Context to stop early.
+// If the first copy fails before the delay, the second one is started right away;
+// the Promise is rejected only if both copies fail, with the error of the last one.
+// Both copies are reported to observers as attempts of the same task, until the task is settled:
+// the events of the losing copy after that are dropped.
+// WithMemo, WithDiskCache and WithCheckpoint apply to the task as a whole, like for Async;
+// WithRetry is not supported, and panics, since the second copy already retries the first.
+// On a Strand, whose tasks run one at a time, f is called once without hedging.
+// Usage example: p := pas.Hedge[string](fetch, 50*time.Millisecond, url)
+func Hedge[T any](f interface{}, after time.Duration, args ...interface{}) *Promise[T] {
+	fv, args, recursive, cfg, injectCtx := parseCall("Hedge", f, args)
+	cfg.rejectOptions("Hedge", "WithRetry")
+
+	p := newPending[T]()
+	t := newTask(p.id, fv, len(args), false, cfg)
+	t.injectCtx = injectCtx
+	p.task = t
+
+	go func() {
+		output, err := executeHedged[T](t, f, after, recursive, args)
+		p.settle(output, err)
+	}()
+
+	return p
+}
+
+// hedgeResult is the outcome of one copy of a hedged task.
+type hedgeResult[T any] struct {
+	output T
+	err    error
+	copy   *task
+}
+
+// executeHedged resolves the arguments of f once, and races up to two copies of f on them,
+// through the task's memo, disk cache and checkpoint.
+func executeHedged[T any](t *task, f interface{}, after time.Duration, recursive bool, args []interface{}) (output T, err error) {
+	defer t.finish(&err)
+
+	fv := checkFunction(f)
+	if t.checkpoint != nil {
+		if output, ok, err := restore[T](t); ok || err != nil {
+			return output, err
+		}
+	}
+	resolvedArgs, err := resolveArgs(t, fv, recursive, args)
+	if err != nil {
+		return output, err
+	}
+	return callWrapped[T](t, fv, resolvedArgs, func() (T, error) {
+		return raceCopies[T](t, fv, after, resolvedArgs)
+	})
+}
+
+// raceCopies calls the function of t, and a second copy of it if the first has not succeeded after the given delay.
+// On a Strand, the function is only called once.
+func raceCopies[T any](t *task, fv reflect.Value, after time.Duration, resolvedArgs []reflect.Value) (output T, err error) {
+	if t.turn != nil {
+		if output, err = callFunction[T](t, fv, resolvedArgs); err == nil {
+			err = t.lateArgsErr()
+		}
+		return output, err
+	}
+
+	// Each copy runs on its own copy of the task, so that their attempts are recorded independently
+	results := make(chan hedgeResult[T], 2)
+	var cancels []context.CancelFunc
+	settled := &atomic.Bool{}
+	launch := func() {
+		ctx, cancel := context.WithCancel(t.ctx)
+		c := *t
+		c.ctx = ctx
+		c.attempt = len(cancels)
+		c.observer = hedgeObserver{t.observer, settled}
+		cancels = append(cancels, cancel)
+		go func() {
+			output, err := callFunction[T](&c, fv, resolvedArgs)
+			results <- hedgeResult[T]{output, err, &c}
+		}()
+	}
+	defer func() {
+		settled.Store(true)
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	launch()
+	timer := time.NewTimer(after)
+	defer timer.Stop()
+	for running := 1; ; {
+		select {
+		case <-timer.C:
+			if len(cancels) == 1 {
+				launch()
+				running++
+			}
+		case r := <-results:
+			running--
+			if r.err == nil || (running == 0 && len(cancels) == 2) {
+				t.attempt, t.started, t.finished, t.attemptEnded = r.copy.attempt, r.copy.started, r.copy.finished, true
+				if r.err == nil {
+					return r.output, t.lateArgsErr()
+				}
+				return r.output, r.err
+			}
+			if len(cancels) == 1 {
+				launch()
+				running++
+			}
+		}
+	}
+}
+
+// hedgeObserver forwards the events of a copy of a hedged task, until the task is settled.
+type hedgeObserver struct {
+	Observer
+	settled *atomic.Bool
+}
+
+func (o hedgeObserver) OnStart(info TaskInfo) {
+	if !o.settled.Load() {
+		o.Observer.OnStart(info)
+	}
+}
+
+func (o hedgeObserver) OnFinish(info TaskInfo, duration time.Duration, err error) {
+	if !o.settled.Load() {
+		o.Observer.OnFinish(info, duration, err)
+	}
+}
+
+func (o hedgeObserver) OnPanic(info TaskInfo, value interface{}, stack []byte) {
+	if !o.settled.Load() {
+		o.Observer.OnPanic(info, value, stack)
+	}
+}
@@ -0,0 +1,89 @@
+package pas
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestHedge verifies that a slow task is raced by a second copy on the same resolved arguments,
+// and that the losing copy is cancelled.
+func TestHedge(t *testing.T) {
+	var calls atomic.Int32
+	cancelled := make(chan struct{})
+	stall := func(ctx context.Context, n int) int {
+		if calls.Add(1) == 1 {
+			<-ctx.Done()
+			close(cancelled)
+			return -1
+		}
+		return n
+	}
+	obs := newCountingObserver()
+	p := Hedge[int](stall, 10*time.Millisecond, Async[int](Square, 3), WithObserver(obs))
+	if val := p.Get(); val != 9 {
+		t.Errorf("Expected 9, got %d", val)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the losing copy to be cancelled")
+	}
+	time.Sleep(10 * time.Millisecond) // Let the losing copy return
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if obs.events["resolved"] != 1 || obs.events["start"] != 2 || obs.events["finish"] != 1 {
+		t.Errorf("Expected 2 attempts after resolving once, and the loser not to finish after the task, got %v", obs.events)
+	}
+
+	// A fast task is not hedged
+	calls.Store(0)
+	if val := Hedge[int](Square, time.Second, 4).Get(); val != 16 {
+		t.Errorf("Expected 16, got %d", val)
+	}
+}
+
+// TestHedgeFailure verifies that a failure of the first copy starts the second one,
+// and that the task is rejected when both fail.
+func TestHedgeFailure(t *testing.T) {
+	var calls atomic.Int32
+	if val, err := Hedge[int](flaky(1, &calls), time.Hour, 5).Result(); err != nil || val != 10 {
+		t.Errorf("Expected 10, got %d (%v)", val, err)
+	}
+	if err := Hedge[int](Fail, time.Millisecond, 1).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected transient error, got %v", err)
+	}
+}
+
+// TestHedgeOptions verifies that the memo and checkpoint Options apply to a hedged task, and that WithRetry is rejected.
+func TestHedgeOptions(t *testing.T) {
+	var calls atomic.Int32
+	count := func(n int) int {
+		calls.Add(1)
+		return n * 2
+	}
+	memo := NewMemo(MemoPolicy{})
+	Hedge[int](count, time.Hour, 1, WithMemo(memo)).Get()
+	if val := Hedge[int](count, time.Hour, 1, WithMemo(memo)).Get(); val != 2 || calls.Load() != 1 {
+		t.Errorf("Expected 2 from the memo after a single call, got %d after %d calls", val, calls.Load())
+	}
+
+	cp, err := OpenCheckpoint(filepath.Join(t.TempDir(), "run.ckpt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Hedge[int](count, time.Hour, 2, WithName("count"), WithCheckpoint(cp)).Get()
+	if val := Hedge[int](Fail, time.Hour, 2, WithName("count"), WithCheckpoint(cp)).Get(); val != 4 {
+		t.Errorf("Expected 4 restored from the checkpoint, got %d", val)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected WithRetry to be rejected")
+		}
+	}()
+	Hedge[int](count, time.Hour, 3, WithRetry(RetryPolicy{MaxAttempts: 2}))
+}
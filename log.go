@@ -0,0 +1,111 @@
+package pas
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// defaultLogger is the logger set by SetLogger, or nil to use slog.Default().
+var defaultLogger atomic.Pointer[slog.Logger]
+
+// SetLogger sets the package default logger, used by calls without a WithLogger option.
+// Passing nil restores the default, slog.Default(), which writes to standard error.
+// To silence the package, pass a logger whose handler discards all records.
+func SetLogger(l *slog.Logger) {
+	defaultLogger.Store(l)
+}
+
+// WithLogger sets the logger for the call, overriding the package default set by SetLogger.
+func WithLogger(l *slog.Logger) Option {
+	return optionFunc(func(c *config) {
+		c.logger = l
+	})
+}
+
+// loggerFor returns the logger for a call: its WithLogger option, or the package default.
+func loggerFor(cfg *config) *slog.Logger {
+	if cfg.logger != nil {
+		return cfg.logger
+	}
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// LogObserver logs task lifecycle events to the logger of each task (see TaskInfo.Logger).
+// It is the default global observer.
+// Panics of Async tasks are logged at the error level; other events are logged at the debug level.
+// Panics in Sync are logged at the debug level only, since they propagate to the caller.
+type LogObserver struct{}
+
+// enabled reports whether the task's logger records events at level, so that attributes are only built when needed.
+func (LogObserver) enabled(info TaskInfo, level slog.Level) bool {
+	return info.Logger.Enabled(context.Background(), level)
+}
+
+// attrs returns the attributes identifying the task in log records.
+func (LogObserver) attrs(info TaskInfo) []slog.Attr {
+	attrs := []slog.Attr{slog.Uint64("task", info.ID)}
+	if info.Name != "" {
+		attrs = append(attrs, slog.String("name", info.Name))
+	}
+	return append(attrs, slog.String("func", info.Func), slog.String("site", info.Site))
+}
+
+// OnCreate logs the creation of the task.
+func (o LogObserver) OnCreate(info TaskInfo) {
+	if !o.enabled(info, slog.LevelDebug) {
+		return
+	}
+	info.Logger.LogAttrs(context.Background(), slog.LevelDebug, "pas: task created",
+		append(o.attrs(info), slog.Int("args", info.NumArgs))...)
+}
+
+// OnArgsResolved logs that the task's arguments are ready.
+func (o LogObserver) OnArgsResolved(info TaskInfo) {
+	if !o.enabled(info, slog.LevelDebug) {
+		return
+	}
+	info.Logger.LogAttrs(context.Background(), slog.LevelDebug, "pas: task arguments resolved",
+		append(o.attrs(info), slog.Duration("waited", time.Since(info.Created)))...)
+}
+
+// OnStart logs the start of the task's function.
+func (o LogObserver) OnStart(info TaskInfo) {
+	if !o.enabled(info, slog.LevelDebug) {
+		return
+	}
+	attrs := o.attrs(info)
+	if info.Throttled > 0 {
+		attrs = append(attrs, slog.Duration("throttled", info.Throttled))
+	}
+	info.Logger.LogAttrs(context.Background(), slog.LevelDebug, "pas: task started", attrs...)
+}
+
+// OnFinish logs the end of the task.
+func (o LogObserver) OnFinish(info TaskInfo, duration time.Duration, err error) {
+	if !o.enabled(info, slog.LevelDebug) {
+		return
+	}
+	attrs := append(o.attrs(info), slog.Duration("duration", duration))
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	info.Logger.LogAttrs(context.Background(), slog.LevelDebug, "pas: task finished", attrs...)
+}
+
+// OnPanic logs the panic of the task with its stack trace.
+func (o LogObserver) OnPanic(info TaskInfo, value interface{}, stack []byte) {
+	level := slog.LevelError
+	if info.Sync {
+		level = slog.LevelDebug
+	}
+	if !o.enabled(info, level) {
+		return
+	}
+	info.Logger.LogAttrs(context.Background(), level, "pas: task panicked",
+		append(o.attrs(info), slog.Any("panic", value), slog.String("stack", string(stack)))...)
+}
@@ -0,0 +1,41 @@
+package pas
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestLogObserverPanic verifies that the default observer logs panics to the call's logger.
+func TestLogObserverPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	obs := newCountingObserver()
+	Async[int](Explode, 1, WithLogger(logger), WithObserver(obs))
+	<-obs.finished
+
+	out := buf.String()
+	for _, want := range []string{"level=ERROR", "pas: task panicked", "func=github.com/AnJunHao/pas.Explode", "panic=boom", "stack="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestSetLogger verifies that the package default logger is used without WithLogger,
+// and that debug records include the task's duration.
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	Sync[int](Square, 4)
+
+	out := buf.String()
+	for _, want := range []string{"pas: task created", "pas: task started", "pas: task finished", "duration=", "func=github.com/AnJunHao/pas.Square"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package pas
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Eviction selects which entry a full Memo discards to make room for a new one.
+type Eviction int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU Eviction = iota
+	// FIFO evicts the oldest entry.
+	FIFO
+)
+
+// MemoPolicy configures a Memo.
+type MemoPolicy struct {
+	// Capacity is the maximum number of cached results. Zero means no limit.
+	Capacity int
+	// Eviction selects the entry discarded when the Memo is full.
+	Eviction Eviction
+	// Key derives the cache key from the resolved arguments of a call, for arguments that are not comparable.
+	// It must return a comparable value. A nil Key uses the arguments themselves, which must then be comparable.
+	// The function being called is always part of the key.
+	Key func(args []interface{}) interface{}
+}
+
+// MemoStats counts the lookups of a Memo.
+type MemoStats struct {
+	Hits      int // Calls that shared a cached or in-flight result
+	Misses    int // Calls that invoked the function
+	Evictions int // Results discarded to respect the capacity
+}
+
+// Memo shares the results of calls with equal functions and resolved arguments.
+// A call whose key is already cached, or being computed by another task, waits for that result
+// instead of invoking the function again. Failed results are shared with the calls that were waiting,
+// but are not cached.
+// The function is identified by its code pointer, so all closures created by the same function literal
+// share their results; use separate Memos or a custom Key to tell them apart.
+// Usage example:
+// m := pas.NewMemo(pas.MemoPolicy{Capacity: 1000})
+// p := pas.Async[int](Expensive, x, pas.WithMemo(m))
+type Memo struct {
+	policy MemoPolicy
+
+	mu      sync.Mutex
+	entries map[memoKey]*memoEntry
+	order   *list.List // Entries from the most to the least recently used or inserted
+	stats   MemoStats
+}
+
+// memoKey identifies a call in a Memo.
+type memoKey struct {
+	fn   uintptr
+	args interface{}
+}
+
+// memoEntry is a result of a Memo, which is being computed until ready is closed.
+type memoEntry struct {
+	key   memoKey
+	elem  *list.Element
+	ready chan struct{}
+	value interface{}
+	err   error
+}
+
+// NewMemo creates an empty Memo with the given policy.
+func NewMemo(policy MemoPolicy) *Memo {
+	return &Memo{
+		policy:  policy,
+		entries: make(map[memoKey]*memoEntry),
+		order:   list.New(),
+	}
+}
+
+// WithMemo shares the task's result through m with every other task calling the same function
+// with equal resolved arguments.
+func WithMemo(m *Memo) Option {
+	return optionFunc(func(c *config) {
+		c.memo = m
+	})
+}
+
+// Stats returns the lookup counts of the Memo so far.
+func (m *Memo) Stats() MemoStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// Len returns the number of cached results, including those being computed.
+func (m *Memo) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// Forget removes all cached results.
+// Calls already waiting for a result being computed still receive it.
+func (m *Memo) Forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[memoKey]*memoEntry)
+	m.order.Init()
+}
+
+// key returns the key of a call to fn with the given resolved arguments.
+func (m *Memo) key(fn reflect.Value, args []reflect.Value) (memoKey, error) {
+	if m.policy.Key != nil {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg.Interface()
+		}
+		key := m.policy.Key(values)
+		if key != nil && !reflect.ValueOf(key).Comparable() {
+			return memoKey{}, fmt.Errorf("pas: memo key of type %T is not comparable", key)
+		}
+		return memoKey{fn.Pointer(), key}, nil
+	}
+
+	// Store the arguments in an array of interfaces, which is comparable if they all are
+	array := reflect.New(reflect.ArrayOf(len(args), reflect.TypeOf((*interface{})(nil)).Elem())).Elem()
+	for i, arg := range args {
+		if !arg.Comparable() {
+			return memoKey{}, fmt.Errorf("pas: argument %d of type %s is not comparable; set MemoPolicy.Key to memoize this call", i, arg.Type())
+		}
+		array.Index(i).Set(arg)
+	}
+	return memoKey{fn.Pointer(), array.Interface()}, nil
+}
+
+// do returns the shared result of the call to fn with the given resolved arguments,
+// invoking call if no other task has computed or is computing it.
+func (m *Memo) do(fn reflect.Value, args []reflect.Value, call func() (interface{}, error)) (interface{}, error) {
+	key, err := m.key(fn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if e, ok := m.entries[key]; ok {
+		if m.policy.Eviction == LRU {
+			m.order.MoveToFront(e.elem)
+		}
+		m.stats.Hits++
+		m.mu.Unlock()
+		<-e.ready
+		return e.value, e.err
+	}
+	e := &memoEntry{key: key, ready: make(chan struct{})}
+	e.elem = m.order.PushFront(e)
+	m.entries[key] = e
+	m.stats.Misses++
+	for m.policy.Capacity > 0 && len(m.entries) > m.policy.Capacity {
+		m.remove(m.order.Back().Value.(*memoEntry))
+		m.stats.Evictions++
+	}
+	m.mu.Unlock()
+
+	defer close(e.ready)
+	e.value, e.err = call()
+	if e.err != nil {
+		m.mu.Lock()
+		if m.entries[key] == e {
+			m.remove(e)
+		}
+		m.mu.Unlock()
+	}
+	return e.value, e.err
+}
+
+// remove discards an entry. The Memo must be locked.
+func (m *Memo) remove(e *memoEntry) {
+	delete(m.entries, e.key)
+	m.order.Remove(e.elem)
+}
+
+// memoize returns the result of call shared through m.
+func memoize[T any](m *Memo, fn reflect.Value, args []reflect.Value, call func() (T, error)) (output T, err error) {
+	value, err := m.do(fn, args, func() (interface{}, error) {
+		return call()
+	})
+	if value != nil {
+		output = value.(T)
+	}
+	return output, err
+}
@@ -0,0 +1,83 @@
+package pas
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMemoSharesCalls verifies that concurrent calls with equal resolved arguments share one invocation,
+// even when the arguments come from different promises.
+func TestMemoSharesCalls(t *testing.T) {
+	var calls atomic.Int32
+	slowSquare := func(n int) int {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return n * n
+	}
+	m := NewMemo(MemoPolicy{})
+	a := Async[int](slowSquare, Async[int](Add, 1, 2), WithMemo(m))
+	b := Async[int](slowSquare, Async[int](Add, 2, 1), WithMemo(m))
+	c := Async[int](slowSquare, 4, WithMemo(m))
+	if a.Get() != 9 || b.Get() != 9 || c.Get() != 16 {
+		t.Errorf("Expected 9, 9 and 16, got %d, %d and %d", a.Get(), b.Get(), c.Get())
+	}
+	if val := Sync[int](slowSquare, 3, WithMemo(m)); val != 9 {
+		t.Errorf("Expected the cached 9, got %d", val)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected 2 calls, got %d", n)
+	}
+	if stats := m.Stats(); stats.Hits != 2 || stats.Misses != 2 {
+		t.Errorf("Expected 2 hits and 2 misses, got %+v", stats)
+	}
+}
+
+// TestMemoEviction verifies the capacity of a Memo under both eviction policies.
+func TestMemoEviction(t *testing.T) {
+	for _, eviction := range []Eviction{LRU, FIFO} {
+		m := NewMemo(MemoPolicy{Capacity: 2, Eviction: eviction})
+		Sync[int](Square, 1, WithMemo(m))
+		Sync[int](Square, 2, WithMemo(m))
+		Sync[int](Square, 1, WithMemo(m)) // Hit, which makes 2 the least recently used
+		Sync[int](Square, 3, WithMemo(m)) // Evicts 2 under LRU, and 1 under FIFO
+		Sync[int](Square, 1, WithMemo(m))
+
+		stats := m.Stats()
+		expectedHits := map[Eviction]int{LRU: 2, FIFO: 1}[eviction]
+		if stats.Hits != expectedHits || stats.Evictions < 1 || m.Len() != 2 {
+			t.Errorf("Eviction %d: expected %d hits with 2 entries, got %+v with %d entries", eviction, expectedHits, stats, m.Len())
+		}
+	}
+}
+
+// TestMemoKeys verifies custom keys, non-comparable arguments, and that failures are not cached.
+func TestMemoKeys(t *testing.T) {
+	m := NewMemo(MemoPolicy{})
+	if err := Async[int](SumSlice, []int{1, 2}, WithMemo(m)).Err(); err == nil {
+		t.Errorf("Expected an error for a non-comparable argument")
+	}
+
+	var calls atomic.Int32
+	sum := func(nums []int) int {
+		calls.Add(1)
+		return SumSlice(nums)
+	}
+	byLength := NewMemo(MemoPolicy{Key: func(args []interface{}) interface{} {
+		return len(args[0].([]int))
+	}})
+	Sync[int](sum, []int{1, 2}, WithMemo(byLength))
+	if val := Sync[int](sum, []int{3, 4}, WithMemo(byLength)); val != 3 || calls.Load() != 1 {
+		t.Errorf("Expected the result cached under the custom key, got %d after %d calls", val, calls.Load())
+	}
+
+	var failures atomic.Int32
+	m = NewMemo(MemoPolicy{})
+	if err := Async[int](flaky(1, &failures), 1, WithMemo(m)).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected transient error, got %v", err)
+	}
+	if val := Async[int](flaky(1, &failures), 1, WithMemo(m)).Get(); val != 2 {
+		t.Errorf("Expected the failed call to be retried, got %d", val)
+	}
+}
@@ -0,0 +1,143 @@
+package pas
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// TaskInfo describes the Async or Sync invocation an Observer is notified about.
+type TaskInfo struct {
+	ID        uint64 // Unique identifier, shared with the Promise returned by Async
+	Name      string // Name set by WithName, or empty
+	Site      string // File and line where Async or Sync was called
+	Func      string // Function symbol, as reported by runtime.FuncForPC
+	NumArgs   int
+	Sync      bool
+	Attempt   int           // Number of the current attempt, starting at 1, or 0 before the function is first called
+	Throttled time.Duration // Time the current attempt waited for RateLimiters before calling the function
+	Created   time.Time
+	Logger    *slog.Logger // The logger of the call, set by WithLogger or SetLogger
+}
+
+// Observer receives lifecycle events of Async and Sync invocations.
+// Callbacks are made from the goroutine running the task and must be safe for concurrent use.
+type Observer interface {
+	// OnCreate is called when Async or Sync is invoked.
+	OnCreate(info TaskInfo)
+	// OnArgsResolved is called once every Promise among the arguments is ready.
+	OnArgsResolved(info TaskInfo)
+	// OnStart is called right before each attempt at invoking the function.
+	OnStart(info TaskInfo)
+	// OnFinish is called at the end of each attempt, with the execution time of the function,
+	// or once when the task fails before the function is invoked.
+	// err is non-nil if the attempt or the task failed.
+	OnFinish(info TaskInfo, duration time.Duration, err error)
+	// OnPanic is called when the task panics, with the recovered value and the stack trace.
+	OnPanic(info TaskInfo, value interface{}, stack []byte)
+}
+
+// NopObserver ignores all events.
+// Embed it in a struct to implement only some of the Observer methods.
+type NopObserver struct{}
+
+func (NopObserver) OnCreate(TaskInfo)                       {}
+func (NopObserver) OnArgsResolved(TaskInfo)                 {}
+func (NopObserver) OnStart(TaskInfo)                        {}
+func (NopObserver) OnFinish(TaskInfo, time.Duration, error) {}
+func (NopObserver) OnPanic(TaskInfo, interface{}, []byte)   {}
+
+// observerHolder wraps the global Observer so that it can be stored in an atomic.Value
+// regardless of its dynamic type.
+type observerHolder struct {
+	Observer
+}
+
+// globalObserver holds the Observer notified of every task.
+var globalObserver atomic.Value
+
+func init() {
+	globalObserver.Store(observerHolder{LogObserver{}})
+}
+
+// SetObserver sets the Observer notified of every task, replacing the default LogObserver.
+// Passing nil disables global observation.
+// Use MultiObserver to register several observers at once.
+func SetObserver(o Observer) {
+	if o == nil {
+		o = NopObserver{}
+	}
+	globalObserver.Store(observerHolder{o})
+}
+
+// multiObserver forwards every event to each of its observers in order.
+type multiObserver []Observer
+
+// MultiObserver returns an Observer that forwards every event to each of obs in order.
+func MultiObserver(obs ...Observer) Observer {
+	return multiObserver(append([]Observer(nil), obs...))
+}
+
+func (m multiObserver) OnCreate(info TaskInfo) {
+	for _, o := range m {
+		o.OnCreate(info)
+	}
+}
+
+func (m multiObserver) OnArgsResolved(info TaskInfo) {
+	for _, o := range m {
+		o.OnArgsResolved(info)
+	}
+}
+
+func (m multiObserver) OnStart(info TaskInfo) {
+	for _, o := range m {
+		o.OnStart(info)
+	}
+}
+
+func (m multiObserver) OnFinish(info TaskInfo, duration time.Duration, err error) {
+	for _, o := range m {
+		o.OnFinish(info, duration, err)
+	}
+}
+
+func (m multiObserver) OnPanic(info TaskInfo, value interface{}, stack []byte) {
+	for _, o := range m {
+		o.OnPanic(info, value, stack)
+	}
+}
+
+// observerFor returns the Observer for a call: the global one, followed by those of the call's Options.
+func observerFor(cfg *config) Observer {
+	global := globalObserver.Load().(observerHolder).Observer
+	if len(cfg.observers) == 0 {
+		return global
+	}
+	return multiObserver(append([]Observer{global}, cfg.observers...))
+}
+
+// PanicError is the error reported for a task whose function panicked.
+type PanicError struct {
+	Value interface{} // The value passed to panic
+	Stack []byte      // The stack trace of the panicking goroutine
+	Task  TaskInfo    // The task that panicked
+}
+
+// Error returns a description of the panic, naming the task and where it was created.
+func (e *PanicError) Error() string {
+	name := e.Task.Name
+	if name == "" {
+		name = e.Task.Func
+	}
+	return fmt.Sprintf("pas: task %q (created at %s) panicked: %v", name, e.Task.Site, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package pas
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingObserver counts the events it receives.
+type countingObserver struct {
+	mu       sync.Mutex
+	events   map[string]int
+	errs     []error
+	finished chan struct{}
+}
+
+func newCountingObserver() *countingObserver {
+	return &countingObserver{events: make(map[string]int), finished: make(chan struct{}, 100)}
+}
+
+func (o *countingObserver) count(event string) {
+	o.mu.Lock()
+	o.events[event]++
+	o.mu.Unlock()
+}
+
+func (o *countingObserver) OnCreate(TaskInfo)       { o.count("create") }
+func (o *countingObserver) OnArgsResolved(TaskInfo) { o.count("resolved") }
+func (o *countingObserver) OnStart(TaskInfo)        { o.count("start") }
+func (o *countingObserver) OnPanic(TaskInfo, interface{}, []byte) {
+	o.count("panic")
+}
+func (o *countingObserver) OnFinish(info TaskInfo, d time.Duration, err error) {
+	o.mu.Lock()
+	o.events["finish"]++
+	if err != nil {
+		o.errs = append(o.errs, err)
+	}
+	o.mu.Unlock()
+	o.finished <- struct{}{}
+}
+
+func Explode(n int) int {
+	panic(errors.New("boom"))
+}
+
+// TestObserverLifecycle verifies that a per-call observer receives every lifecycle event.
+func TestObserverLifecycle(t *testing.T) {
+	obs := newCountingObserver()
+	p := Async[int](Square, New(3), WithObserver(obs))
+	if val := p.Get(); val != 9 {
+		t.Errorf("Expected 9, got %d", val)
+	}
+	<-obs.finished
+	Sync[int](Add, p, 1, true, WithObserver(obs))
+
+	for _, event := range []string{"create", "resolved", "start", "finish"} {
+		if obs.events[event] != 2 {
+			t.Errorf("Expected 2 %s events, got %d", event, obs.events[event])
+		}
+	}
+}
+
+// TestObserverPanic verifies that panics are reported through OnPanic and OnFinish,
+// and that the default logging can be replaced.
+func TestObserverPanic(t *testing.T) {
+	obs := newCountingObserver()
+	SetObserver(nil)
+	defer SetObserver(LogObserver{})
+
+	scope := NewScope(WithObserver(obs))
+	Async[int](Explode, 1, scope)
+	<-obs.finished
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if obs.events["panic"] != 1 || len(obs.errs) != 1 {
+		t.Fatalf("Expected 1 panic and 1 error, got %d and %d", obs.events["panic"], len(obs.errs))
+	}
+	var pe *PanicError
+	if !errors.As(obs.errs[0], &pe) || pe.Value.(error).Error() != "boom" {
+		t.Errorf("Expected a PanicError wrapping boom, got %v", obs.errs[0])
+	}
+	if obs.events["start"] != 1 {
+		t.Errorf("Expected 1 start event, got %d", obs.events["start"])
+	}
+}
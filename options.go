@@ -0,0 +1,140 @@
+package pas
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Option configures a single Async or Sync call.
+// Options are passed after the function's arguments and the optional recursive flag.
+// It has an unexported method to prevent external packages from implementing it.
+type Option interface {
+	apply(c *config)
+}
+
+// optionFunc adapts a function to the Option interface.
+type optionFunc func(c *config)
+
+// apply calls f.
+func (f optionFunc) apply(c *config) {
+	f(c)
+}
+
+// config holds the settings of a single call, collected from its Options.
+type config struct {
+	name        string
+	observers   []Observer
+	logger      *slog.Logger
+	timeout     time.Duration
+	waitTimeout time.Duration
+	retry       *RetryPolicy
+	memo        *Memo
+	executor    *Executor
+	priority    int
+	resources   []resourceNeed
+	limiter     *RateLimiter
+	strand      *Strand
+
+	diskCache        *DiskCache
+	diskCacheVersion string
+	checkpoint       *Checkpoint
+
+	grain       int  // Minimum number of elements per chunk of ParallelFor and ParallelMap
+	commutative bool // Whether the combine function of Reduce is commutative
+	buffer      int  // Number of values buffered by a Stream
+}
+
+// splitOptions removes the trailing Options from args and applies them to a new config.
+// Options are only taken from arguments beyond the numRequired arguments of the function,
+// so functions that take an Option as a parameter can still be called.
+func splitOptions(args []interface{}, numRequired int) ([]interface{}, *config) {
+	end := len(args)
+	for end > numRequired {
+		if _, ok := args[end-1].(Option); !ok {
+			break
+		}
+		end--
+	}
+	opts := make([]Option, 0, len(args)-end)
+	for _, arg := range args[end:] {
+		opts = append(opts, arg.(Option))
+	}
+	return args[:end], newConfig(opts)
+}
+
+// newConfig applies opts in order to a new config.
+func newConfig(opts []Option) *config {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	return cfg
+}
+
+// rejectOptions panics if c sets any of the Options named in names, which caller cannot honour.
+func (c *config) rejectOptions(caller string, names ...string) {
+	set := map[string]bool{
+		"WithRetry":      c.retry != nil,
+		"WithMemo":       c.memo != nil,
+		"WithDiskCache":  c.diskCache != nil,
+		"WithCheckpoint": c.checkpoint != nil,
+	}
+	for _, name := range names {
+		if set[name] {
+			panic(fmt.Sprintf("pas.%s: %s is not supported", caller, name))
+		}
+	}
+}
+
+// withOptions appends opts to the arguments of an Async or Sync call.
+func withOptions(args []interface{}, opts []Option) []interface{} {
+	for _, opt := range opts {
+		args = append(args, opt)
+	}
+	return args
+}
+
+// WithName names the task, for debugging.
+// The name appears in panic messages, logs, trace exports, the promise's String and, with SetProfilerLabels, pprof labels.
+func WithName(name string) Option {
+	return optionFunc(func(c *config) {
+		c.name = name
+	})
+}
+
+// WithObserver registers an Observer for the call, in addition to the global one set by SetObserver.
+func WithObserver(o Observer) Option {
+	return optionFunc(func(c *config) {
+		c.observers = append(c.observers, o)
+	})
+}
+
+// Scope is a reusable set of Options, such as the observers of one subsystem.
+// A Scope is itself an Option and is passed to Async or Sync like any other Option.
+// Usage example:
+// scope := pas.NewScope(pas.WithObserver(metrics))
+// p := pas.Async[int](Compute, 5, 10, scope)
+type Scope struct {
+	opts []Option
+}
+
+// NewScope creates a Scope applying the given Options to every call it is passed to.
+func NewScope(opts ...Option) *Scope {
+	return &Scope{opts: opts}
+}
+
+// With returns a new Scope applying the Options of s followed by opts.
+func (s *Scope) With(opts ...Option) *Scope {
+	combined := make([]Option, 0, len(s.opts)+len(opts))
+	combined = append(combined, s.opts...)
+	combined = append(combined, opts...)
+	return &Scope{opts: combined}
+}
+
+// apply applies the Options of the Scope in order.
+func (s *Scope) apply(c *config) {
+	for _, opt := range s.opts {
+		opt.apply(c)
+	}
+}
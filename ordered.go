@@ -0,0 +1,55 @@
+package pas
+
+import "context"
+
+// OrderedMap returns a Stream of f applied to each value of in, which is a slice, a channel or a Stream,
+// in the order of the input. Each call of f is an Async call, to which the Options apply,
+// so f may take a context.Context first, return an error second, and the values may be Promises.
+// At most window values are in flight at once, whether being processed or done and waiting for
+// an earlier value; results are emitted as soon as all the earlier ones are, so the output is never
+// held in memory as a whole. A failure of f ends the Stream with its error and stops reading the input,
+// as does cancelling the returned Stream.
+// Usage example: thumbnails := pas.OrderedMap[Image](photos.C(), Resize, 16)
+func OrderedMap[U any](in interface{}, f interface{}, window int, opts ...Option) *Stream[U] {
+	if window < 1 {
+		window = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	items := feedPipeline(ctx, in, nil)
+
+	return startStream[U](ctx, func(done context.Context, sink *Sink[U]) error {
+		defer cancel()
+		// inflight is the reorder buffer: the Promises of the values not yet emitted, in input order
+		inflight := make([]*Promise[U], 0, window)
+		for items != nil || len(inflight) > 0 {
+			var next <-chan pipelineItem
+			if len(inflight) < window {
+				next = items
+			}
+			var head <-chan struct{}
+			if len(inflight) > 0 {
+				head = inflight[0].ready
+			}
+			select {
+			case <-done.Done():
+				return done.Err()
+			case it, ok := <-next:
+				if !ok {
+					items = nil
+					continue
+				}
+				inflight = append(inflight, Async[U](f, withOptions([]interface{}{it.value}, opts)...))
+			case <-head:
+				p := inflight[0]
+				inflight = inflight[1:]
+				if p.err != nil {
+					return p.err
+				}
+				if err := sink.Emit(p.value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}, nil)
+}
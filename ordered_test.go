@@ -0,0 +1,76 @@
+package pas
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestOrderedMap verifies that results are emitted in input order, with at most window values in flight.
+func TestOrderedMap(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	slow := func(n int) int {
+		r := running.Add(1)
+		for m := maxRunning.Load(); r > m && !maxRunning.CompareAndSwap(m, r); m = maxRunning.Load() {
+		}
+		time.Sleep(time.Duration(5-n%5) * time.Millisecond)
+		running.Add(-1)
+		return n * 10
+	}
+	in := make(chan int)
+	go func() {
+		for i := 0; i < 30; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	next := 0
+	s := OrderedMap[int](in, slow, 4)
+	for v := range s.C() {
+		if v != next*10 {
+			t.Fatalf("Expected %d, got %d", next*10, v)
+		}
+		next++
+	}
+	if err := s.Err(); err != nil || next != 30 {
+		t.Errorf("Expected 30 values without error, got %d (%v)", next, err)
+	}
+	if m := maxRunning.Load(); m > 4 {
+		t.Errorf("Expected at most 4 values in flight, got %d", m)
+	}
+}
+
+// TestOrderedMapFailure verifies that a failure ends the stream after the values before it.
+func TestOrderedMapFailure(t *testing.T) {
+	failAt := func(n int) (int, error) {
+		if n == 2 {
+			return 0, errTransient
+		}
+		return n, nil
+	}
+	s := OrderedMap[int]([]int{0, 1, 2, 3, 4}, failAt, 2)
+	var values []int
+	for v := range s.C() {
+		values = append(values, v)
+	}
+	if len(values) != 2 || !errors.Is(s.Err(), errTransient) {
+		t.Errorf("Expected [0 1] and a transient error, got %v and %v", values, s.Err())
+	}
+}
+
+// TestOrderedMapCancel verifies that cancelling the output stream stops the map and its input.
+func TestOrderedMapCancel(t *testing.T) {
+	in := AsyncStream[int](countForever)
+	out := OrderedMap[int](in, Square, 2)
+	<-out.C()
+	out.Cancel()
+	if err := out.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := in.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the input stream to be cancelled, got %v", err)
+	}
+}
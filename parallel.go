@@ -0,0 +1,139 @@
+package pas
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+// WithGrain sets the minimum number of elements processed by each chunk of ParallelFor and ParallelMap.
+// Raise it when the work per element is small, so that the overhead of a task is amortized over more elements.
+func WithGrain(n int) Option {
+	return optionFunc(func(c *config) {
+		c.grain = n
+	})
+}
+
+// ParallelFor calls body(i) for every i in [0, n), in parallel. It does nothing if n <= 0.
+// The range is split into contiguous chunks, one per GOMAXPROCS, of at least the grain set by WithGrain,
+// and each chunk runs as a task. The Options are applied to every chunk, whose attempts are scheduled,
+// bounded and retried like those of Async; WithMemo, WithDiskCache and WithCheckpoint are not supported, and panic.
+// ParallelFor returns once all chunks are done. Like Sync, it then propagates the panic of body.
+// Usage example: pas.ParallelFor(len(images), func(i int) { thumbnails[i] = Resize(images[i]) })
+func ParallelFor(n int, body func(i int), opts ...Option) {
+	cfg := newConfig(opts)
+	chunks := startChunks("ParallelFor", n, reflect.ValueOf(body), cfg, func(t *task, lo, hi int) (struct{}, error) {
+		for i := lo; i < hi; i++ {
+			body(i)
+		}
+		return struct{}{}, nil
+	})
+	var first error
+	for _, chunk := range chunks {
+		if err := chunk.Err(); err != nil && first == nil {
+			first = err
+		}
+	}
+	if first != nil {
+		var pe *PanicError
+		if errors.As(first, &pe) {
+			panic(pe.Value)
+		}
+		panic(first)
+	}
+}
+
+// ParallelMap returns a Promise resolved with f applied to every element of in, which is either a []T
+// or a []*Promise[T], such as one created by MakeSlice.
+// Elements are split into chunks like in ParallelFor, with the same Options. Each chunk processes its Promises in order
+// as soon as each is ready, without waiting for the rest of the slice.
+// If an element is rejected, or f panics, the returned Promise is rejected.
+// Usage example: lengths := pas.ParallelMap(words, func(w string) int { return len(w) })
+func ParallelMap[T, U any](in interface{}, f func(T) U, opts ...Option) *Promise[[]U] {
+	n, get := sliceSource[T](in)
+	out := make([]U, n)
+	cfg := newConfig(opts)
+	chunks := startChunks("ParallelMap", n, reflect.ValueOf(f), cfg, func(t *task, lo, hi int) (struct{}, error) {
+		for i := lo; i < hi; i++ {
+			value, err := get(t, i)
+			if err != nil {
+				return struct{}{}, err
+			}
+			out[i] = f(value)
+		}
+		return struct{}{}, nil
+	})
+	return Async[[]U](func([]struct{}) []U { return out }, withOptions([]interface{}{chunks, true}, opts)...)
+}
+
+// sliceSource returns the length of in, a []T or a []*Promise[T], and a function returning its elements,
+// waiting on behalf of a task for those that are Promises.
+func sliceSource[T any](in interface{}) (int, func(t *task, i int) (T, error)) {
+	switch s := in.(type) {
+	case []T:
+		return len(s), func(t *task, i int) (T, error) {
+			return s[i], nil
+		}
+	case []*Promise[T]:
+		return len(s), func(t *task, i int) (T, error) {
+			return awaitTyped(t, s[i])
+		}
+	default:
+		var zero T
+		panic(fmt.Sprintf("pas.ParallelMap: expected []%T or []*Promise[%T], but got %T", zero, zero, in))
+	}
+}
+
+// chunkSize returns the number of elements in each chunk of a loop over n elements.
+func chunkSize(n, grain int) int {
+	procs := runtime.GOMAXPROCS(0)
+	size := (n + procs - 1) / procs
+	if size < grain {
+		size = grain
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// startChunks splits [0, n) into chunks, and starts a task running body on each of them.
+// Each chunk's Promise is resolved with the value returned by body. There are no chunks if n <= 0.
+// fn describes the tasks to observers. caller names the function in the panic for an unsupported Option.
+func startChunks[R any](caller string, n int, fn reflect.Value, cfg *config, body func(t *task, lo, hi int) (R, error)) []*Promise[R] {
+	// Chunks have no arguments to key a cache on, nor a name of their own to checkpoint under
+	cfg.rejectOptions(caller, "WithMemo", "WithDiskCache", "WithCheckpoint")
+	if n <= 0 {
+		return nil
+	}
+	size := chunkSize(n, cfg.grain)
+	chunks := make([]*Promise[R], 0, (n+size-1)/size)
+	for lo := 0; lo < n; lo += size {
+		hi := min(lo+size, n)
+		p := newPending[R]()
+		t := newTask(p.id, fn, 1, false, cfg)
+		p.task = t
+		go func() {
+			output, err := runChunk(t, lo, hi, body)
+			p.settle(output, err)
+		}()
+		chunks = append(chunks, p)
+	}
+	return chunks
+}
+
+// runChunk runs body on a chunk as the function of the task t, through the same attempts as that of Async.
+// Errors from the Promises it waits for are propagated unchanged.
+func runChunk[R any](t *task, lo, hi int, body func(t *task, lo, hi int) (R, error)) (output R, err error) {
+	defer t.finish(&err)
+	defer t.stopWaiting()
+	t.argsResolved = time.Now()
+	t.observer.OnArgsResolved(t.info())
+
+	call := func() (R, error) {
+		return body(t, lo, hi)
+	}
+	return callWithRetry[R](t, reflect.ValueOf(call), nil)
+}
@@ -0,0 +1,109 @@
+package pas
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParallelFor verifies that every index is visited exactly once, whatever the grain.
+func TestParallelFor(t *testing.T) {
+	for _, grain := range []int{0, 1, 7, 1000} {
+		visits := make([]atomic.Int32, 100)
+		ParallelFor(len(visits), func(i int) { visits[i].Add(1) }, WithGrain(grain))
+		for i := range visits {
+			if n := visits[i].Load(); n != 1 {
+				t.Fatalf("Grain %d: expected index %d to be visited once, got %d", grain, i, n)
+			}
+		}
+	}
+	ParallelFor(0, func(i int) { t.Errorf("Expected no calls") })
+	ParallelFor(-1, func(i int) { t.Errorf("Expected no calls") })
+
+	SetObserver(nil)
+	defer SetObserver(LogObserver{})
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	var done atomic.Int32
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("Expected the panic of the body, got %v", r)
+		}
+		if n := done.Load(); n != 7 { // Chunks of 3, the first stopping at the panic
+			t.Errorf("Expected the other iterations to be done before the panic, got %d", n)
+		}
+	}()
+	ParallelFor(10, func(i int) {
+		if i == 0 {
+			panic("boom")
+		}
+		time.Sleep(5 * time.Millisecond)
+		done.Add(1)
+	})
+}
+
+// TestParallelForOptions verifies that the chunks are scheduled and retried according to the Options,
+// and that the Options chunks cannot honour are rejected.
+func TestParallelForOptions(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	var g gauge
+	var failed atomic.Bool
+	visits := make([]atomic.Int32, 8)
+	ParallelFor(len(visits), func(i int) {
+		defer g.enter()()
+		if i == 0 && !failed.Swap(true) {
+			panic(errTransient)
+		}
+		time.Sleep(time.Millisecond)
+		visits[i].Add(1)
+	}, WithExecutor(NewExecutor(1)), WithRetry(RetryPolicy{MaxAttempts: 2}))
+	for i := range visits {
+		if n := visits[i].Load(); n != 1 {
+			t.Errorf("Expected index %d to be visited once, got %d", i, n)
+		}
+	}
+	if m := g.max.Load(); m != 1 {
+		t.Errorf("Expected the chunks to run one at a time on the Executor, got %d at once", m)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected WithMemo to be rejected")
+		}
+	}()
+	ParallelFor(8, func(i int) {}, WithMemo(NewMemo(MemoPolicy{})))
+}
+
+// TestParallelMap verifies mapping over values and over promises, including rejected ones.
+func TestParallelMap(t *testing.T) {
+	values := make([]int, 50)
+	for i := range values {
+		values[i] = i
+	}
+	squares := ParallelMap(values, func(n int) int { return n * n }, WithGrain(3)).Get()
+	for i, sq := range squares {
+		if sq != i*i {
+			t.Fatalf("Expected %d at index %d, got %d", i*i, i, sq)
+		}
+	}
+
+	promises := MakeSlice[int](10)
+	for i := range promises {
+		promises[i] = Async[int](SleepContext, 10-i)
+	}
+	doubled := ParallelMap(promises, func(n int) int { return 2 * n }).Get()
+	for i, d := range doubled {
+		if d != 2*(10-i) {
+			t.Fatalf("Expected %d at index %d, got %d", 2*(10-i), i, d)
+		}
+	}
+
+	promises[3] = Async[int](Fail, 1)
+	if err := ParallelMap(promises, func(n int) int { return n }).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the rejection of an element, got %v", err)
+	}
+	if val := ParallelMap([]string{}, func(s string) int { return len(s) }).Get(); len(val) != 0 {
+		t.Errorf("Expected an empty result, got %v", val)
+	}
+}
@@ -1,49 +1,129 @@
 package pas
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 )
 
 // promiseTypeContract is an internal interface that identifies a Promise.
 // It has an unexported method to prevent external packages from implementing it.
 type promiseTypeContract interface { // unexported
-	get() interface{}
+	get() (interface{}, error)
+	readyChan() <-chan struct{}
+	promiseID() uint64
+	producer() *task
 }
 
 // Promise represents a parallel variable holding a value of type T.
+// A Promise is either resolved with a value, or rejected with an error.
 type Promise[T any] struct {
+	id    uint64
+	task  *task // The task producing the value, or nil for promises created by New
 	value T
+	err   error
 	ready chan struct{}
 	once  sync.Once
 }
 
 // Get returns the computed value, blocking until it is ready.
+// If the Promise was rejected, Get panics with the error; use Result to handle failures.
 func (p *Promise[T]) Get() T {
-	<-p.ready
-	return p.value
+	value, err := p.Result()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Result returns the computed value and the error the Promise was rejected with, blocking until it is settled.
+func (p *Promise[T]) Result() (T, error) {
+	select {
+	case <-p.ready:
+	default:
+		if d := activeDetector.Load(); d != nil {
+			defer d.getterBlocked(p.id, callerSite())()
+		}
+		<-p.ready
+	}
+	return p.value, p.err
+}
+
+// Err returns the error the Promise was rejected with, or nil, blocking until it is settled.
+func (p *Promise[T]) Err() error {
+	_, err := p.Result()
+	return err
 }
 
 // resolve sets the value of the Promise and marks it as ready.
 // It can only be called once; subsequent calls will have no effect.
 func (p *Promise[T]) resolve(value T) {
+	p.settle(value, nil)
+}
+
+// reject sets the error of the Promise and marks it as ready.
+// It can only be called once; subsequent calls will have no effect.
+func (p *Promise[T]) reject(err error) {
+	p.settle(*new(T), err)
+}
+
+// settle sets the value and error of the Promise and marks it as ready, if it is not already.
+func (p *Promise[T]) settle(value T, err error) {
 	p.once.Do(func() {
-		p.value = value
+		p.value, p.err = value, err
 		close(p.ready)
+		if d := activeDetector.Load(); d != nil {
+			d.settled(p.id)
+		}
 	})
 }
 
 // get is an unexported method to satisfy the promiseTypeContract interface.
-// It retrieves the value held by the promise, blocking until it's ready.
-func (p *Promise[T]) get() interface{} {
+// It retrieves the value and error held by the promise, blocking until it's ready.
+func (p *Promise[T]) get() (interface{}, error) {
 	<-p.ready
-	return p.value
+	return p.value, p.err
+}
+
+// readyChan returns the channel closed when the promise is settled.
+func (p *Promise[T]) readyChan() <-chan struct{} {
+	return p.ready
+}
+
+// promiseID returns the unique identifier of the promise.
+func (p *Promise[T]) promiseID() uint64 {
+	return p.id
+}
+
+// producer returns the task producing the promise, or nil if it was created by New.
+func (p *Promise[T]) producer() *task {
+	return p.task
+}
+
+// String describes the promise with its name, creation site and state, for debugging.
+// It does not block.
+func (p *Promise[T]) String() string {
+	desc := fmt.Sprintf("Promise[%T]#%d", *new(T), p.id)
+	if p.task != nil {
+		desc += " " + p.task.describe()
+	}
+	select {
+	case <-p.ready:
+		if p.err != nil {
+			return fmt.Sprintf("%s: rejected (%v)", desc, p.err)
+		}
+		return fmt.Sprintf("%s: ready (%v)", desc, p.value)
+	default:
+		return desc + ": pending"
+	}
 }
 
 // New creates a pointer to a new Promise holding a value of type T.
 func New[T any](values ...T) *Promise[T] {
-	p := &Promise[T]{ready: make(chan struct{})}
+	p := &Promise[T]{id: nextID(), ready: make(chan struct{})}
 	if len(values) == 0 {
 		// Do not set p.value; leave it zero-valued
 	} else if len(values) == 1 {
@@ -59,23 +139,78 @@ func New[T any](values ...T) *Promise[T] {
 
 // newPending creates a pointer to a new Promise holding a value of type T that is not yet ready.
 func newPending[T any]() *Promise[T] {
-	return &Promise[T]{ready: make(chan struct{})}
+	return &Promise[T]{id: nextID(), ready: make(chan struct{})}
 }
 
-// Async starts a parallel computation by invoking function f with the provided arguments.
-// If any argument is a Promise, it waits for it to be ready before executing f.
-// It enforces that function f has exactly one return value of type T.
-// It accepts an optional boolean flag as the last argument to enable recursive resolving.
-func Async[T any](f interface{}, args ...interface{}) *Promise[T] {
+// contextType is the reflect.Type of context.Context.
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// errorType is the reflect.Type of error.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// requiredArgs returns the number of arguments function type ft expects from the caller.
+// If the function takes a context.Context as its first parameter, the task's context is injected as the first
+// argument, and the second return value is true, when args, without the recursive flag and the Options,
+// has one argument less than the function's parameters. A first argument standing for the context,
+// whether a context.Context, a Promise of one or nil, is passed as is when the number of arguments allows it.
+func requiredArgs(ft reflect.Type, args []interface{}) (int, bool) {
+	n := ft.NumIn()
+	if n == 0 || ft.In(0) != contextType {
+		return n, false
+	}
+	if len(args) > 0 && standsForContext(args[0]) && numCallArgs(args, n) == n {
+		return n, false
+	}
+	if numCallArgs(args, n-1) == n-1 {
+		return n - 1, true
+	}
+	return n, false
+}
+
+// standsForContext reports whether arg may be passed for a context.Context parameter.
+func standsForContext(arg interface{}) bool {
+	switch arg.(type) {
+	case nil, context.Context:
+		return true
+	case promiseTypeContract:
+		value, ok := reflect.TypeOf(arg).Elem().FieldByName("value")
+		return ok && value.Type.Implements(contextType)
+	}
+	return false
+}
+
+// numCallArgs returns the number of arguments among args for a function taking numRequired parameters,
+// that is without the trailing Options and the recursive flag, as parseCall separates them.
+func numCallArgs(args []interface{}, numRequired int) int {
+	end := len(args)
+	for end > numRequired {
+		if _, ok := args[end-1].(Option); !ok {
+			break
+		}
+		end--
+	}
+	if end == numRequired+1 {
+		if _, ok := args[end-1].(bool); ok {
+			end--
+		}
+	}
+	return end
+}
+
+// parseCall validates f and separates the arguments passed to Async or Sync into the function's arguments,
+// the recursive flag and the Options. caller names the public function in panic messages.
+// The last return value reports whether the task's context is injected as the function's first argument.
+func parseCall(caller string, f interface{}, args []interface{}) (reflect.Value, []interface{}, bool, *config, bool) {
 	var recursive bool
 
 	// Detect if the last argument is a boolean flag for recursive resolving
 	fv := reflect.ValueOf(f)
 	if fv.Kind() != reflect.Func {
-		panic(fmt.Sprintf("Async: expected a function, but got %T", f))
+		panic(fmt.Sprintf("%s: expected a function, but got %T", caller, f))
 	}
 	ft := fv.Type()
-	numRequiredArgs := ft.NumIn()
+	numRequiredArgs, injectCtx := requiredArgs(ft, args)
+	args, cfg := splitOptions(args, numRequiredArgs)
 
 	if len(args) == numRequiredArgs+1 {
 		if flag, ok := args[len(args)-1].(bool); ok {
@@ -85,23 +220,34 @@ func Async[T any](f interface{}, args ...interface{}) *Promise[T] {
 	}
 
 	if len(args) != numRequiredArgs {
-		panic(fmt.Sprintf("Async: function expects %d arguments, but got %d", numRequiredArgs, len(args)))
+		panic(fmt.Sprintf("%s: function expects %d arguments, but got %d", caller, numRequiredArgs, len(args)))
 	}
+	return fv, args, recursive, cfg, injectCtx
+}
+
+// Async starts a parallel computation by invoking function f with the provided arguments.
+// If any argument is a Promise, it waits for it to be ready before executing f.
+// If any Promise is rejected, or f panics, the returned Promise is rejected.
+// It enforces that function f has exactly one return value of type T, optionally followed by an error
+// which rejects the returned Promise when non-nil.
+// It accepts an optional boolean flag after the arguments to enable recursive resolving,
+// followed by any number of Options.
+// If f takes a context.Context as its first parameter, it may be omitted from the arguments;
+// the injected context is cancelled when the task times out.
+func Async[T any](f interface{}, args ...interface{}) *Promise[T] {
+	fv, args, recursive, cfg, injectCtx := parseCall("Async", f, args)
 
 	p := newPending[T]()
+	t := newTask(p.id, fv, len(args), false, cfg)
+	t.injectCtx = injectCtx
+	p.task = t
 
 	// Start a goroutine to execute the function in parallel
 	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				// Handle panics gracefully; in production, consider logging or handling differently
-				fmt.Printf("Async: function execution panicked: %v\n", r)
-			}
-		}()
 		// Execute the function and get the result
-		output := executeFunction[T](f, recursive, args...)
+		output, err := executeFunction[T](t, f, recursive, args...)
 		// Assign the result to the Promise and signal readiness
-		p.resolve(output)
+		p.settle(output, err)
 	}()
 
 	return p
@@ -109,74 +255,152 @@ func Async[T any](f interface{}, args ...interface{}) *Promise[T] {
 
 // Sync executes function f synchronously with the provided arguments.
 // If any argument is a Promise, it waits for it to be ready before executing f.
-// It enforces that function f has exactly one return value of type T.
-// It accepts an optional boolean flag as the last argument to enable recursive resolving.
+// If any Promise is rejected, or f returns a non-nil error, Sync panics with the error; panics of f are propagated.
+// It enforces that function f has exactly one return value of type T, optionally followed by an error.
+// It accepts an optional boolean flag after the arguments to enable recursive resolving,
+// followed by any number of Options.
+// If f takes a context.Context as its first parameter, it may be omitted from the arguments.
 func Sync[T any](f interface{}, args ...interface{}) T {
-	var recursive bool
+	fv, args, recursive, cfg, injectCtx := parseCall("Sync", f, args)
 
-	// Detect if the last argument is a boolean flag for recursive resolving
-	fv := reflect.ValueOf(f)
-	if fv.Kind() != reflect.Func {
-		panic(fmt.Sprintf("Sync: expected a function, but got %T", f))
+	// Execute the function and return the result
+	t := newTask(nextID(), fv, len(args), true, cfg)
+	t.injectCtx = injectCtx
+	output, err := executeFunction[T](t, f, recursive, args...)
+	if err != nil {
+		// Propagate panics of f itself with their original value
+		var pe *PanicError
+		if errors.As(err, &pe) && pe.Task.ID == t.id {
+			panic(pe.Value)
+		}
+		panic(err)
 	}
-	ft := fv.Type()
-	numRequiredArgs := ft.NumIn()
+	return output
+}
 
-	if len(args) == numRequiredArgs+1 {
-		if flag, ok := args[len(args)-1].(bool); ok {
-			recursive = flag
-			args = args[:len(args)-1] // Remove the flag from args
+// executeFunction is a helper that encapsulates the common logic for Async and Sync.
+// It validates the function, resolves arguments based on the expected parameter types,
+// invokes the function, and asserts the return type.
+// The 'recursive' flag determines whether to resolve promises recursively.
+// Every promise found among the arguments is reported to the task t as a dependency.
+// Panics are recovered and returned as a *PanicError.
+func executeFunction[T any](t *task, f interface{}, recursive bool, args ...interface{}) (output T, err error) {
+	defer t.finish(&err)
+	fv := checkFunction(f)
+	if t.checkpoint != nil {
+		if output, ok, err := restore[T](t); ok || err != nil {
+			return output, err
 		}
 	}
+	resolvedArgs, err := resolveArgs(t, fv, recursive, args)
+	if err != nil {
+		return output, err
+	}
+	// Plain calls skip the wrappers: the function runs on a small goroutine stack,
+	// and every frame on the way may make it grow, which costs more than the call itself.
+	if t.retry == nil && t.checkpoint == nil && t.diskCache == nil && t.memo == nil {
+		output, err = callFunction[T](t, fv, resolvedArgs)
+		if err == nil {
+			err = t.lateArgsErr()
+		}
+		return output, err
+	}
+	return callWrapped[T](t, fv, resolvedArgs, func() (T, error) {
+		return callWithRetry[T](t, fv, resolvedArgs)
+	})
+}
 
-	if len(args) != numRequiredArgs {
-		panic(fmt.Sprintf("Sync: function expects %d arguments, but got %d", numRequiredArgs, len(args)))
+// callWrapped runs call, which calls the function, through the task's memo, disk cache and checkpoint, outermost first.
+func callWrapped[T any](t *task, fv reflect.Value, resolvedArgs []reflect.Value, call func() (T, error)) (T, error) {
+	if t.checkpoint != nil {
+		uncheckpointed := call
+		call = func() (T, error) {
+			return checkpointed(t, uncheckpointed)
+		}
+	}
+	if t.diskCache != nil {
+		uncached := call
+		call = func() (T, error) {
+			return loadOrCall(t, resolvedArgs, uncached)
+		}
+	}
+	if t.memo != nil {
+		return memoize(t.memo, fv, resolvedArgs, call)
 	}
+	return call()
+}
 
-	// Execute the function and return the result
-	return executeFunction[T](f, recursive, args...)
+// callWithRetry calls the function, retrying with the same resolved arguments as allowed by the task's retry policy.
+func callWithRetry[T any](t *task, fv reflect.Value, resolvedArgs []reflect.Value) (output T, err error) {
+	for {
+		output, err = callFunction[T](t, fv, resolvedArgs)
+		if err == nil {
+			// Channel arguments cannot be consumed again, so their failure is not retried
+			return output, t.lateArgsErr()
+		}
+		if !t.retry.shouldRetry(t.attempt, err) {
+			return output, err
+		}
+		if t.detached != nil {
+			<-t.detached // Attempts never overlap, even when one exceeds its budget
+		}
+		time.Sleep(t.retry.backoff(t.attempt))
+	}
 }
 
-// executeFunction is a helper that encapsulates the common logic for Async and Sync.
-// It validates the function, resolves arguments based on the expected parameter types,
-// invokes the function, and asserts the return type.
-// The 'recursive' flag determines whether to resolve promises recursively.
-func executeFunction[T any](f interface{}, recursive bool, args ...interface{}) T {
+// checkFunction validates that f is a function with exactly one return value, optionally followed by an error.
+func checkFunction(f interface{}) reflect.Value {
 	fv := reflect.ValueOf(f)
-	ft := fv.Type()
 
 	// Validate that f is a function
 	if fv.Kind() != reflect.Func {
 		panic(fmt.Sprintf("pas.executeFunction: expected a function, but got %T", f))
 	}
 
-	// Enforce that f has exactly one return value
-	if ft.NumOut() != 1 {
-		panic(fmt.Sprintf("pas.executeFunction: function must have exactly one return value, but got %d values", ft.NumOut()))
+	// Enforce that f has exactly one return value, optionally followed by an error
+	ft := fv.Type()
+	if ft.NumOut() != 1 && !(ft.NumOut() == 2 && ft.Out(1) == errorType) {
+		panic(fmt.Sprintf("pas.executeFunction: function must have exactly one return value, optionally followed by an error, but got %d values", ft.NumOut()))
+	}
+	return fv
+}
+
+// resolveArgs resolves arguments based on the expected parameter types and the 'recursive' flag.
+// It waits for every promise among the arguments, within the task's wait budget.
+// If a promise was rejected, its error is returned as-is, so that failures propagate unchanged along the graph.
+func resolveArgs(t *task, fv reflect.Value, recursive bool, args []interface{}) ([]reflect.Value, error) {
+	ft := fv.Type()
+	offset := 0
+	if t.injectCtx {
+		offset = 1
 	}
 
 	// Enforce that the number of arguments matches
-	if ft.NumIn() != len(args) {
-		panic(fmt.Sprintf("pas.executeFunction: function expects %d arguments, but got %d", ft.NumIn(), len(args)))
+	if ft.NumIn() != len(args)+offset {
+		panic(fmt.Sprintf("pas.executeFunction: function expects %d arguments, but got %d", ft.NumIn()-offset, len(args)))
 	}
 
-	// Resolve arguments based on the expected parameter types and the 'recursive' flag
+	defer t.stopWaiting()
 	resolvedArgs := make([]reflect.Value, len(args))
 	for i, arg := range args {
-		expectedType := ft.In(i)
+		expectedType := ft.In(i + offset)
 		var resolved interface{}
 		var err error
 
 		if recursive {
 			// Recursive resolving using resolveValue
-			resolved, err = resolveValue(arg, expectedType)
+			resolved, err = resolveValue(t, arg, expectedType)
 		} else {
 			// Shallow resolving: only resolve top-level promises
-			resolved, err = shallowResolve(arg, expectedType)
+			resolved, err = shallowResolve(t, arg, expectedType)
 		}
 
 		if err != nil {
-			panic(fmt.Sprintf("pas.executeFunction: error resolving argument %d: %v", i, err))
+			var rejected *rejectedError
+			if errors.As(err, &rejected) {
+				return nil, rejected.err
+			}
+			return nil, fmt.Errorf("pas.executeFunction: error resolving argument %d: %w", i, err)
 		}
 
 		// Handle nil inputs by setting zero value if necessary
@@ -190,33 +414,68 @@ func executeFunction[T any](f interface{}, recursive bool, args ...interface{})
 				if resolvedVal.Type().ConvertibleTo(expectedType) {
 					resolvedVal = resolvedVal.Convert(expectedType)
 				} else {
-					panic(fmt.Sprintf("pas.executeFunction: argument %d has type %s, expected %s",
-						i, resolvedVal.Type(), expectedType))
+					return nil, fmt.Errorf("pas.executeFunction: argument %d has type %s, expected %s",
+						i, resolvedVal.Type(), expectedType)
 				}
 			}
 			resolvedArgs[i] = resolvedVal
 		}
 	}
 
+	t.argsResolved = time.Now()
+	t.observer.OnArgsResolved(t.info())
+	return resolvedArgs, nil
+}
+
+// callFunction makes one attempt at invoking the task's function with its resolved arguments,
+// and asserts the return type. The attempt first waits for the task to be scheduled.
+// If the function returns a non-nil error as its second value, that error is returned.
+// If the task has an execution budget, the function's context is cancelled and a timeout error
+// is returned once the budget is exceeded, without waiting for the function to return.
+// The function then keeps its resources and Executor worker until it returns, and t.detached is set.
+// Panics are recovered and returned as a *PanicError.
+func callFunction[T any](t *task, fv reflect.Value, resolvedArgs []reflect.Value) (output T, err error) {
+	t.detached = nil
+	release := t.schedule()
+	ctx, cancel := t.execContext()
+	defer func() {
+		cancel()
+		afterReturn(t.detached, release)
+	}()
+	in := t.callArgs(ctx, resolvedArgs)
+
 	// Call the function with the resolved arguments
-	results := fv.Call(resolvedArgs)
-	if len(results) != 1 {
-		panic(fmt.Sprintf("pas.executeFunction: function must return exactly one value, but got %d values", len(results)))
+	t.startAttempt()
+	defer t.endAttempt(&err)
+	var results []reflect.Value
+	if t.timeout <= 0 && !profilerLabels.Load() {
+		results = fv.Call(in) // Called directly, for the same reason as the wrappers are skipped in executeFunction
+	} else if results, t.detached = t.invoke(ctx, fv, in); t.detached != nil {
+		return output, t.timeoutError("executing", t.timeout)
 	}
+	return returnValue[T](results)
+}
 
-	// Assert that the return type matches T
+// returnValue returns the value and error returned by the task's function,
+// and asserts that the return type matches T, treating a nil interface as the zero value.
+func returnValue[T any](results []reflect.Value) (output T, err error) {
+	if len(results) == 2 && !results[1].IsNil() {
+		return output, results[1].Interface().(error)
+	}
+	if results[0].Kind() == reflect.Interface && results[0].IsNil() {
+		return output, nil
+	}
 	output, ok := results[0].Interface().(T)
 	if !ok {
 		panic(fmt.Sprintf("pas.executeFunction: return type of function does not match generic type. Expected %T, got %T",
 			*new(T), results[0].Interface()))
 	}
-
-	return output
+	return output, nil
 }
 
 // shallowResolve resolves only the top-level promises without delving into nested structures.
 // It returns the resolved value or the original value if it's not a promise.
-func shallowResolve(input interface{}, expectedType reflect.Type) (interface{}, error) {
+func shallowResolve(t *task, input interface{}, expectedType reflect.Type) (interface{}, error) {
 	if input == nil {
 		// Return zero value of expectedType
 		return reflect.Zero(expectedType).Interface(), nil
@@ -224,8 +483,12 @@ func shallowResolve(input interface{}, expectedType reflect.Type) (interface{},
 
 	// Handle Promise
 	if promise, ok := input.(promiseTypeContract); ok {
-		resolved := promise.get()
-		return resolved, nil
+		return t.await(promise)
+	}
+
+	// Handle Stream
+	if stream, ok := input.(streamContract); ok {
+		return resolveStream(t, stream, expectedType)
 	}
 
 	// If not a Promise, return as-is
@@ -233,9 +496,9 @@ func shallowResolve(input interface{}, expectedType reflect.Type) (interface{},
 }
 
 // resolveValue recursively resolves Promises within the input based on the expectedType.
-// It handles Promises, pointers, slices, arrays, maps, and nested combinations thereof.
+// It handles Promises, pointers, slices, arrays, maps, channels, and nested combinations thereof.
 // expectedType defines the type that the resolved value should conform to.
-func resolveValue(input interface{}, expectedType reflect.Type) (interface{}, error) {
+func resolveValue(t *task, input interface{}, expectedType reflect.Type) (interface{}, error) {
 	if input == nil {
 		// Return zero value of expectedType
 		return reflect.Zero(expectedType).Interface(), nil
@@ -243,8 +506,20 @@ func resolveValue(input interface{}, expectedType reflect.Type) (interface{}, er
 
 	// Handle Promise
 	if promise, ok := input.(promiseTypeContract); ok {
-		resolved := promise.get()
-		return resolveValue(resolved, expectedType)
+		resolved, err := t.await(promise)
+		if err != nil {
+			return nil, err
+		}
+		return resolveValue(t, resolved, expectedType)
+	}
+
+	// Handle Stream
+	if stream, ok := input.(streamContract); ok {
+		resolved, err := resolveStream(t, stream, expectedType)
+		if err != nil {
+			return nil, err
+		}
+		return resolveValue(t, resolved, expectedType)
 	}
 
 	currentType := reflect.TypeOf(input)
@@ -258,7 +533,7 @@ func resolveValue(input interface{}, expectedType reflect.Type) (interface{}, er
 		if reflect.ValueOf(input).IsNil() {
 			return reflect.Zero(expectedType).Interface(), nil
 		}
-		resolvedElem, err := resolveValue(reflect.ValueOf(input).Elem().Interface(), expectedType.Elem())
+		resolvedElem, err := resolveValue(t, reflect.ValueOf(input).Elem().Interface(), expectedType.Elem())
 		if err != nil {
 			return nil, err
 		}
@@ -277,9 +552,9 @@ func resolveValue(input interface{}, expectedType reflect.Type) (interface{}, er
 		}
 		newSlice := reflect.MakeSlice(expectedType, inputVal.Len(), inputVal.Len())
 		for i := 0; i < inputVal.Len(); i++ {
-			resolvedElem, err := resolveValue(inputVal.Index(i).Interface(), expectedType.Elem())
+			resolvedElem, err := resolveValue(t, inputVal.Index(i).Interface(), expectedType.Elem())
 			if err != nil {
-				return nil, fmt.Errorf("error resolving slice element at index %d: %v", i, err)
+				return nil, fmt.Errorf("error resolving slice element at index %d: %w", i, err)
 			}
 			newSlice.Index(i).Set(reflect.ValueOf(resolvedElem))
 		}
@@ -296,9 +571,9 @@ func resolveValue(input interface{}, expectedType reflect.Type) (interface{}, er
 		}
 		newArray := reflect.New(expectedType).Elem()
 		for i := 0; i < inputVal.Len(); i++ {
-			resolvedElem, err := resolveValue(inputVal.Index(i).Interface(), expectedType.Elem())
+			resolvedElem, err := resolveValue(t, inputVal.Index(i).Interface(), expectedType.Elem())
 			if err != nil {
-				return nil, fmt.Errorf("error resolving array element at index %d: %v", i, err)
+				return nil, fmt.Errorf("error resolving array element at index %d: %w", i, err)
 			}
 			newArray.Index(i).Set(reflect.ValueOf(resolvedElem))
 		}
@@ -313,19 +588,33 @@ func resolveValue(input interface{}, expectedType reflect.Type) (interface{}, er
 		newMap := reflect.MakeMapWithSize(expectedType, inputVal.Len())
 		for _, key := range inputVal.MapKeys() {
 			// Resolve the key
-			resolvedKey, err := resolveValue(key.Interface(), expectedType.Key())
+			resolvedKey, err := resolveValue(t, key.Interface(), expectedType.Key())
 			if err != nil {
-				return nil, fmt.Errorf("error resolving map key %v: %v", key.Interface(), err)
+				return nil, fmt.Errorf("error resolving map key %v: %w", key.Interface(), err)
 			}
 			// Resolve the value
-			resolvedValue, err := resolveValue(inputVal.MapIndex(key).Interface(), expectedType.Elem())
+			resolvedValue, err := resolveValue(t, inputVal.MapIndex(key).Interface(), expectedType.Elem())
 			if err != nil {
-				return nil, fmt.Errorf("error resolving map value for key %v: %v", resolvedKey, err)
+				return nil, fmt.Errorf("error resolving map value for key %v: %w", resolvedKey, err)
 			}
 			newMap.SetMapIndex(reflect.ValueOf(resolvedKey), reflect.ValueOf(resolvedValue))
 		}
 		return newMap.Interface(), nil
 
+	case reflect.Chan:
+		// Handle Channel Types, feeding a new channel with the resolved elements
+		inputVal := reflect.ValueOf(input)
+		if inputVal.Kind() != reflect.Chan {
+			return nil, fmt.Errorf("expected a channel, but got %s", inputVal.Kind())
+		}
+		if inputVal.Type().AssignableTo(expectedType) {
+			return input, nil
+		}
+		if inputVal.Type().ChanDir()&reflect.RecvDir == 0 {
+			return nil, fmt.Errorf("cannot receive from %s", inputVal.Type())
+		}
+		return bridgeChan(t, inputVal, expectedType).Interface(), nil
+
 	case reflect.Interface:
 		// If the expected type is interface{}, return the input as-is after resolving any Promises
 		return input, nil
@@ -353,7 +642,7 @@ func shallowResolveArgs(args ...interface{}) []reflect.Value {
 		// Type assertion to check if arg implements promiseTypeContract
 		if promiseArg, ok := arg.(promiseTypeContract); ok {
 			// Retrieve the value from the promise
-			value := promiseArg.get()
+			value, _ := promiseArg.get()
 			resolved[i] = reflect.ValueOf(value)
 		} else {
 			// Use the argument as-is
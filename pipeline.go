@@ -0,0 +1,282 @@
+package pas
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pipeline is a sequence of stages, each processing the values output by the previous one
+// with a fixed number of workers. Stages are connected by bounded queues, so a slow stage
+// slows down the stages before it instead of accumulating values in memory.
+// Build a Pipeline with NewPipeline and Stage, and run it with RunPipeline.
+// Usage example:
+// p := pas.NewPipeline().Stage(Decode, 4).Stage(Resize, 8).Stage(Encode, 4).Ordered()
+// out := pas.RunPipeline[[]byte](ctx, p, files)
+type Pipeline struct {
+	stages  []*pipelineStage
+	ordered bool
+}
+
+// pipelineStage is a stage of a Pipeline, with the metrics of all its runs.
+type pipelineStage struct {
+	f       interface{}
+	fv      reflect.Value
+	workers int
+	cfg     *config
+
+	processed atomic.Int64
+	failed    atomic.Int64
+	busy      atomic.Int64 // Nanoseconds spent in the function
+}
+
+// StageMetrics describes the work done by a stage of a Pipeline over all its runs.
+type StageMetrics struct {
+	Name      string        // Name set with WithName, if any
+	Func      string        // Symbol of the stage's function
+	Workers   int           // Number of workers of the stage
+	Processed int64         // Values processed successfully
+	Failed    int64         // Values whose processing failed
+	Busy      time.Duration // Total time spent in the stage's function, over all workers
+}
+
+// pipelineItem is a value flowing through a Pipeline, with its position in the input.
+type pipelineItem struct {
+	seq   int
+	value interface{}
+}
+
+// NewPipeline returns an empty Pipeline.
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Stage appends a stage calling f on each value with the given number of workers, and returns the Pipeline.
+// f takes a single argument, optionally preceded by a context.Context which is cancelled when the run is,
+// and returns one value, optionally followed by an error. Like with Async, Promises among the values are resolved.
+// Each call is a task, to which the Options apply; WithBuffer sets the size of the queue after the stage,
+// which defaults to the number of workers.
+func (p *Pipeline) Stage(f interface{}, workers int, opts ...Option) *Pipeline {
+	fv := checkFunction(f)
+	if numArgs, _ := requiredArgs(fv.Type(), []interface{}{nil}); numArgs != 1 {
+		panic(fmt.Sprintf("pas.Pipeline.Stage: function must take exactly one argument, but takes %d", numArgs))
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	cfg := newConfig(opts)
+	if cfg.buffer == 0 {
+		cfg.buffer = workers
+	}
+	p.stages = append(p.stages, &pipelineStage{f: f, fv: fv, workers: workers, cfg: cfg})
+	return p
+}
+
+// Ordered makes the Pipeline output its values in the order of its input, and returns the Pipeline.
+// By default, values are output as soon as they are processed.
+// Values processed ahead of an earlier one wait for it in a reorder buffer. To bound the buffer, no more input is read
+// while as many values are in flight as the stages have workers and queue slots, so a slow value holds back the input.
+func (p *Pipeline) Ordered() *Pipeline {
+	p.ordered = true
+	return p
+}
+
+// window returns the number of values an ordered run keeps in flight: enough to keep every worker and queue busy.
+func (p *Pipeline) window() int {
+	n := 0
+	for _, st := range p.stages {
+		n += st.workers + st.cfg.buffer
+	}
+	return max(n, 1)
+}
+
+// Metrics returns the metrics of each stage, in order.
+func (p *Pipeline) Metrics() []StageMetrics {
+	metrics := make([]StageMetrics, len(p.stages))
+	for i, st := range p.stages {
+		metrics[i] = StageMetrics{
+			Name:      st.cfg.name,
+			Func:      funcName(st.fv),
+			Workers:   st.workers,
+			Processed: st.processed.Load(),
+			Failed:    st.failed.Load(),
+			Busy:      time.Duration(st.busy.Load()),
+		}
+	}
+	return metrics
+}
+
+// RunPipeline runs p on the values of in, which is a slice, a channel or a *Stream,
+// and returns a Stream of the values output by the last stage.
+// The first failure of a stage cancels the run and ends the Stream with its error;
+// cancelling ctx, or the returned Stream, ends the Stream with ctx.Err().
+// On cancellation, no more values are read from in, the values being processed are finished,
+// the values waiting in the queues are discarded, and all the goroutines of the run exit.
+func RunPipeline[U any](ctx context.Context, p *Pipeline, in interface{}) *Stream[U] {
+	ctx, cancel := context.WithCancel(ctx)
+	var failure atomic.Pointer[error]
+	fail := func(err error) {
+		if failure.CompareAndSwap(nil, &err) {
+			cancel()
+		}
+	}
+
+	// In ordered mode, each value takes a slot until it is output, which bounds the reorder buffer
+	var slots chan struct{}
+	if p.ordered {
+		slots = make(chan struct{}, p.window())
+	}
+	queue := feedPipeline(ctx, in, slots)
+	for _, st := range p.stages {
+		queue = st.run(ctx, queue, fail)
+	}
+
+	return startStream[U](ctx, func(sink *Sink[U]) error {
+		defer cancel()
+		pending := make(map[int]interface{}) // Reorder buffer of the ordered mode
+		next := 0
+		emit := func(value interface{}) {
+			var typed U
+			if value != nil {
+				typed = value.(U)
+			}
+			if err := sink.Emit(typed); err != nil {
+				cancel() // The Stream was cancelled, or so was the run, since its context derives from ctx
+			}
+		}
+		for it := range queue {
+			if ctx.Err() != nil {
+				continue // Drain
+			}
+			if !p.ordered {
+				emit(it.value)
+				continue
+			}
+			pending[it.seq] = it.value
+			for value, ok := pending[next]; ok; value, ok = pending[next] {
+				delete(pending, next)
+				emit(value)
+				<-slots
+				next++
+			}
+		}
+		if err := failure.Load(); err != nil {
+			return *err
+		}
+		return ctx.Err()
+	}, nil)
+}
+
+// feedPipeline sends the values of in to the first queue of a run, until in is exhausted or ctx is cancelled.
+// If slots is not nil, a slot is taken before reading each value, and the consumer of the run frees it.
+func feedPipeline(ctx context.Context, in interface{}, slots chan<- struct{}) <-chan pipelineItem {
+	var next func() (interface{}, bool)
+	switch v := reflect.ValueOf(in); {
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		i := 0
+		next = func() (interface{}, bool) {
+			if i == v.Len() {
+				return nil, false
+			}
+			i++
+			return v.Index(i - 1).Interface(), true
+		}
+	case v.Kind() == reflect.Chan:
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: v},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		}
+		next = func() (interface{}, bool) {
+			chosen, value, ok := reflect.Select(cases)
+			if chosen != 0 || !ok {
+				return nil, false
+			}
+			return value.Interface(), true
+		}
+	default:
+		if s, ok := in.(streamContract); ok {
+			context.AfterFunc(ctx, s.Cancel) // The producer stops once the run no longer reads from it
+			return feedPipeline(ctx, s.elements().Interface(), slots)
+		}
+		panic(fmt.Sprintf("pas.RunPipeline: expected a slice, a channel or a stream, but got %T", in))
+	}
+
+	out := make(chan pipelineItem)
+	go func() {
+		defer close(out)
+		for seq := 0; ; seq++ {
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			value, ok := next()
+			if !ok {
+				return
+			}
+			select {
+			case out <- pipelineItem{seq, value}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// run starts the workers of the stage, processing the values of in, and returns the queue of their results.
+// The queue is closed once in is closed and all the workers are done.
+// Once ctx is cancelled, the workers discard the values of in, to let the previous stages exit.
+func (st *pipelineStage) run(ctx context.Context, in <-chan pipelineItem, fail func(error)) <-chan pipelineItem {
+	out := make(chan pipelineItem, st.cfg.buffer)
+	var wg sync.WaitGroup
+	wg.Add(st.workers)
+	for w := 0; w < st.workers; w++ {
+		go func() {
+			defer wg.Done()
+			for it := range in {
+				if ctx.Err() != nil {
+					continue // Drain
+				}
+				value, err := st.call(ctx, it.value)
+				if err != nil {
+					fail(err)
+					continue
+				}
+				select {
+				case out <- pipelineItem{it.seq, value}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// call invokes the stage's function on a value as a task, resolving it like Sync does.
+func (st *pipelineStage) call(ctx context.Context, value interface{}) (interface{}, error) {
+	args := []interface{}{value}
+	_, injectCtx := requiredArgs(st.fv.Type(), args)
+	t := newTask(nextID(), st.fv, 1, true, st.cfg)
+	t.ctx = ctx
+	t.injectCtx = injectCtx
+
+	start := time.Now()
+	output, err := executeFunction[interface{}](t, st.f, false, args...)
+	st.busy.Add(int64(time.Since(start)))
+	if err != nil {
+		st.failed.Add(1)
+		return nil, err
+	}
+	st.processed.Add(1)
+	return output, nil
+}
@@ -0,0 +1,134 @@
+package pas
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPipelineOrdered verifies that an ordered pipeline outputs values in input order,
+// whatever the time each takes, and records per-stage metrics.
+func TestPipelineOrdered(t *testing.T) {
+	jitter := func(ctx context.Context, n int) int {
+		time.Sleep(time.Duration(n%3) * time.Millisecond)
+		return n
+	}
+	p := NewPipeline().
+		Stage(jitter, 4).
+		Stage(Square, 2, WithName("square"), WithBuffer(1)).
+		Stage(strconv.Itoa, 1).
+		Ordered()
+	input := make([]interface{}, 20)
+	for i := range input {
+		input[i] = i
+	}
+	input[5] = Async[int](SleepContext, 5) // Promises among the values are resolved
+
+	out := RunPipeline[string](context.Background(), p, input).Collect().Get()
+	if len(out) != 20 {
+		t.Fatalf("Expected 20 values, got %v", out)
+	}
+	for i, s := range out {
+		if s != strconv.Itoa(i*i) {
+			t.Fatalf("Expected %d at index %d, got %s", i*i, i, s)
+		}
+	}
+
+	metrics := p.Metrics()
+	if len(metrics) != 3 || metrics[1].Name != "square" || metrics[1].Workers != 2 || metrics[2].Processed != 20 {
+		t.Errorf("Unexpected metrics: %+v", metrics)
+	}
+}
+
+// TestPipelineOrderedBound verifies that a slow value holds back the input of an ordered pipeline,
+// instead of letting the values processed after it pile up in the reorder buffer.
+func TestPipelineOrderedBound(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	stall := func(n int) int {
+		calls.Add(1)
+		if n == 0 {
+			<-release
+		}
+		return n
+	}
+	p := NewPipeline().Stage(stall, 2, WithBuffer(3)).Ordered()
+	input := make([]int, 100)
+	for i := range input {
+		input[i] = i
+	}
+	out := RunPipeline[int](context.Background(), p, input).Collect()
+
+	time.Sleep(20 * time.Millisecond)
+	if n := calls.Load(); n > 5 {
+		t.Errorf("Expected at most 5 values in flight, got %d", n)
+	}
+	close(release)
+	if values := out.Get(); len(values) != 100 {
+		t.Errorf("Expected 100 values, got %d", len(values))
+	}
+}
+
+// TestPipelineUnordered verifies the unordered mode with a channel as input.
+func TestPipelineUnordered(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		for i := 1; i <= 10; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+	s := RunPipeline[int](context.Background(), NewPipeline().Stage(Square, 3), in)
+	if val := Async[int](SumSlice, s).Get(); val != 385 {
+		t.Errorf("Expected 385, got %d", val)
+	}
+}
+
+// TestPipelineCancellation verifies that a failure or a cancellation ends the output with its error,
+// and that the input is no longer read.
+func TestPipelineCancellation(t *testing.T) {
+	failAt := func(n int) (int, error) {
+		if n == 3 {
+			return 0, errTransient
+		}
+		return n, nil
+	}
+	p := NewPipeline().Stage(failAt, 2).Stage(Square, 2)
+	if err := RunPipeline[int](context.Background(), p, []int{1, 2, 3, 4, 5}).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the stage's failure, got %v", err)
+	}
+	if m := p.Metrics()[0]; m.Failed != 1 {
+		t.Errorf("Expected 1 failure, got %+v", m)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	s := RunPipeline[int](ctx, NewPipeline().Stage(SleepContext, 2), in)
+	in <- 1000
+	cancel()
+	if err := s.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	select {
+	case in <- 1:
+		t.Errorf("Expected the input to no longer be read")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// TestPipelineStreamCancel verifies that cancelling the output stream stops the run and its input stream.
+func TestPipelineStreamCancel(t *testing.T) {
+	in := AsyncStream[int](countForever)
+	out := RunPipeline[int](context.Background(), NewPipeline().Stage(Square, 2), in)
+	<-out.C()
+	out.Cancel()
+	if err := out.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := in.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the input stream to be cancelled, got %v", err)
+	}
+}
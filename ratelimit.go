@@ -0,0 +1,156 @@
+package pas
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// globalLimiter is the RateLimiter set by SetRateLimiter, or nil.
+var globalLimiter atomic.Pointer[RateLimiter]
+
+// namedLimiters are the RateLimiters set by SetRateLimiterFor, by task name.
+var namedLimiters = struct {
+	sync.Mutex
+	byName map[string]*RateLimiter
+}{byName: make(map[string]*RateLimiter)}
+
+// RateLimiter is a token bucket bounding the rate at which tasks start, to avoid overloading the services they call.
+// The bucket holds up to burst tokens and is refilled at rate tokens per second;
+// each attempt of a task takes a token before acquiring its resources and Executor worker,
+// waiting for one if the bucket is empty.
+// RateLimiters apply globally with SetRateLimiter, per task name with SetRateLimiterFor,
+// and per call or Scope with WithRateLimiter. A task subject to several RateLimiters waits for each of them.
+// Usage example:
+// l := pas.NewRateLimiter(50, 10)
+// p := pas.Async[Quote](FetchQuote, symbol, pas.WithRateLimiter(l))
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	stats  RateLimiterStats
+}
+
+// RateLimiterStats describes the tasks that went through a RateLimiter, and how long they were delayed.
+type RateLimiterStats struct {
+	Tasks      int64         // Attempts that took a token
+	Delayed    int64         // Attempts that had to wait for a token
+	TotalDelay time.Duration // Time spent waiting for tokens, over all attempts
+	MaxDelay   time.Duration // Longest wait for a token
+}
+
+// NewRateLimiter creates a RateLimiter letting rate tasks start per second on average, and up to burst at once.
+// It starts full, so the first burst tasks are not delayed. rate must be positive; burst is at least 1.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		panic("pas.NewRateLimiter: rate must be positive")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// SetRateLimiter sets the RateLimiter consulted by every task. Passing nil removes it.
+func SetRateLimiter(l *RateLimiter) {
+	globalLimiter.Store(l)
+}
+
+// SetRateLimiterFor sets the RateLimiter consulted by the tasks named name with WithName. Passing nil removes it.
+func SetRateLimiterFor(name string, l *RateLimiter) {
+	namedLimiters.Lock()
+	defer namedLimiters.Unlock()
+	if l == nil {
+		delete(namedLimiters.byName, name)
+		return
+	}
+	namedLimiters.byName[name] = l
+}
+
+// WithRateLimiter makes the task consult l before each attempt, in addition to the RateLimiters
+// set with SetRateLimiter and SetRateLimiterFor.
+func WithRateLimiter(l *RateLimiter) Option {
+	return optionFunc(func(c *config) {
+		c.limiter = l
+	})
+}
+
+// Stats returns the statistics of the RateLimiter since its creation.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// reserve takes a token from the bucket, and returns how long to wait before it may be used.
+// Tokens are taken in order, so the bucket may go negative while tasks wait for it to refill.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+
+	l.stats.Tasks++
+	if l.tokens >= 0 {
+		return 0
+	}
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.stats.Delayed++
+	l.stats.TotalDelay += delay
+	if delay > l.stats.MaxDelay {
+		l.stats.MaxDelay = delay
+	}
+	return delay
+}
+
+// limitersFor returns the RateLimiters applying to a task: the global one, the one of its name, then its own.
+func limitersFor(t *task) []*RateLimiter {
+	var limiters []*RateLimiter
+	add := func(l *RateLimiter) {
+		if l == nil {
+			return
+		}
+		for _, other := range limiters {
+			if other == l {
+				return
+			}
+		}
+		limiters = append(limiters, l)
+	}
+	add(globalLimiter.Load())
+	if t.name != "" {
+		namedLimiters.Lock()
+		add(namedLimiters.byName[t.name])
+		namedLimiters.Unlock()
+	}
+	add(t.limiter)
+	return limiters
+}
+
+// throttle waits for a token of every RateLimiter applying to the task, and records the delay of the attempt.
+// It stops waiting early if the task's context is cancelled, leaving the function to observe the cancellation.
+func (t *task) throttle() {
+	t.throttled = 0
+	for _, l := range limitersFor(t) {
+		delay := l.reserve()
+		if delay <= 0 {
+			continue
+		}
+		timer := time.NewTimer(delay)
+		start := time.Now()
+		select {
+		case <-timer.C:
+		case <-t.ctx.Done():
+			timer.Stop()
+		}
+		t.throttled += time.Since(start)
+	}
+}
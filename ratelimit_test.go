@@ -0,0 +1,95 @@
+package pas
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// throttleObserver records the delay reported by each started attempt.
+type throttleObserver struct {
+	NopObserver
+	mu        sync.Mutex
+	throttled []time.Duration
+}
+
+func (o *throttleObserver) OnStart(info TaskInfo) {
+	o.mu.Lock()
+	o.throttled = append(o.throttled, info.Throttled)
+	o.mu.Unlock()
+}
+
+// TestRateLimiterScope verifies that tasks of a Scope start at the limiter's rate after its burst,
+// and that their delays are reported to observers and in the limiter's statistics.
+func TestRateLimiterScope(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+	obs := &throttleObserver{}
+	scope := NewScope(WithRateLimiter(l), WithObserver(obs))
+
+	start := time.Now()
+	ps := make([]*Promise[int], 6)
+	for i := range ps {
+		ps[i] = Async[int](Square, i, scope)
+	}
+	for i, p := range ps {
+		if val := p.Get(); val != i*i {
+			t.Errorf("Expected %d, got %d", i*i, val)
+		}
+	}
+	// 4 tasks beyond the burst need 4 tokens, refilled every 10ms
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("Expected the tasks to take at least 35ms, took %v", elapsed)
+	}
+
+	stats := l.Stats()
+	if stats.Tasks != 6 || stats.Delayed < 3 {
+		t.Errorf("Expected 6 tasks with at least 3 delayed, got %+v", stats)
+	}
+	if stats.MaxDelay < 30*time.Millisecond || stats.TotalDelay < stats.MaxDelay {
+		t.Errorf("Expected a maximum delay of at least 30ms, got %+v", stats)
+	}
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	var throttled time.Duration
+	for _, d := range obs.throttled {
+		throttled += d
+	}
+	if len(obs.throttled) != 6 || throttled < 30*time.Millisecond {
+		t.Errorf("Expected the observer to report the delays of 6 tasks, got %v", obs.throttled)
+	}
+}
+
+// TestRateLimiterFor verifies that a limiter set for a task name applies only to the tasks with that name,
+// and that the global limiter applies to every task.
+func TestRateLimiterFor(t *testing.T) {
+	named := NewRateLimiter(1000, 1)
+	SetRateLimiterFor("test-limited", named)
+	defer SetRateLimiterFor("test-limited", nil)
+	global := NewRateLimiter(1000, 100)
+	SetRateLimiter(global)
+	defer SetRateLimiter(nil)
+
+	Sync[int](Square, 2, WithName("test-limited"))
+	Sync[int](Square, 2, WithName("test-limited"))
+	Sync[int](Square, 2, WithName("test-other"))
+	if stats := named.Stats(); stats.Tasks != 2 {
+		t.Errorf("Expected the named limiter to see 2 tasks, got %+v", stats)
+	}
+	if stats := global.Stats(); stats.Tasks < 3 || stats.Delayed != 0 {
+		t.Errorf("Expected the global limiter to see at least 3 undelayed tasks, got %+v", stats)
+	}
+}
+
+// TestRateLimiterBeforeExecutor verifies that a task waiting for a token does not hold its Executor worker.
+func TestRateLimiterBeforeExecutor(t *testing.T) {
+	e := NewExecutor(1)
+	l := NewRateLimiter(10, 1)
+	Sync[int](Square, 1, WithRateLimiter(l)) // Empties the bucket for the next 100ms
+
+	throttled := Async[time.Time](time.Now, WithExecutor(e), WithRateLimiter(l))
+	time.Sleep(10 * time.Millisecond)
+	free := Async[time.Time](time.Now, WithExecutor(e))
+	if !free.Get().Before(throttled.Get()) {
+		t.Errorf("Expected the unthrottled task to run while the other one waits for a token")
+	}
+}
@@ -0,0 +1,86 @@
+package pas
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// Recover returns a new Promise resolved with the value of p, or with handler(err) if p is rejected.
+// Like Async, it accepts Options, except WithMemo, WithDiskCache and WithCheckpoint which panic,
+// and the returned Promise can be passed to Async as any other.
+// Usage example: p := pas.Recover(fetch, func(err error) int { return -1 })
+func Recover[T any](p *Promise[T], handler func(err error) T, opts ...Option) *Promise[T] {
+	return continueWith("Recover", p, reflect.ValueOf(handler), 1, func(t *task, value T, err error) (T, error) {
+		if err != nil {
+			return handler(err), nil
+		}
+		return value, nil
+	}, opts)
+}
+
+// OrElse returns a new Promise resolved with the value of p, or settled like alt if p is rejected.
+// alt is only waited for if p is rejected.
+// Usage example: p := pas.OrElse(fromCache, fromDatabase)
+func OrElse[T any](p *Promise[T], alt *Promise[T], opts ...Option) *Promise[T] {
+	return continueWith("OrElse", p, reflect.ValueOf(OrElse[T]), 2, func(t *task, value T, err error) (T, error) {
+		if err != nil {
+			return awaitTyped(t, alt)
+		}
+		return value, nil
+	}, opts)
+}
+
+// Default returns a new Promise resolved with the value of p, or with v if p is rejected.
+// Usage example: p := pas.Default(fetch, 0)
+func Default[T any](p *Promise[T], v T, opts ...Option) *Promise[T] {
+	return continueWith("Default", p, reflect.ValueOf(Default[T]), 2, func(t *task, value T, err error) (T, error) {
+		if err != nil {
+			return v, nil
+		}
+		return value, nil
+	}, opts)
+}
+
+// continueWith starts a task producing a new Promise from the settled result of p,
+// rather than from its value only, so that f also runs when p is rejected.
+// fn and numArgs describe the task to observers. caller names the function in the panic for an unsupported Option.
+func continueWith[T, U any](caller string, p *Promise[T], fn reflect.Value, numArgs int, f func(t *task, value T, err error) (U, error), opts []Option) *Promise[U] {
+	cfg := newConfig(opts)
+	// A cache or checkpoint would have to be keyed by the result of p, which is only known once the task runs
+	cfg.rejectOptions(caller, "WithMemo", "WithDiskCache", "WithCheckpoint")
+	q := newPending[U]()
+	t := newTask(q.id, fn, numArgs, false, cfg)
+	q.task = t
+	go func() {
+		output, err := runContinuation(t, p, f)
+		q.settle(output, err)
+	}()
+	return q
+}
+
+// runContinuation waits for p to be settled and calls f with its result,
+// as the function of the task t, through the same attempts as that of Async.
+func runContinuation[T, U any](t *task, p *Promise[T], f func(t *task, value T, err error) (U, error)) (output U, err error) {
+	defer t.finish(&err)
+	defer t.stopWaiting()
+	value, perr := awaitTyped(t, p)
+	t.argsResolved = time.Now()
+	t.observer.OnArgsResolved(t.info())
+
+	call := func() (U, error) {
+		return f(t, value, perr)
+	}
+	return callWithRetry[U](t, reflect.ValueOf(call), nil)
+}
+
+// awaitTyped waits for p on behalf of task t, and returns its value and the error it was rejected with.
+func awaitTyped[T any](t *task, p *Promise[T]) (T, error) {
+	value, err := t.await(p)
+	var rejected *rejectedError
+	if errors.As(err, &rejected) {
+		err = rejected.err
+	}
+	typed, _ := value.(T)
+	return typed, err
+}
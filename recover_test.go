@@ -0,0 +1,63 @@
+package pas
+
+import (
+	"errors"
+	"testing"
+)
+
+func Fail(n int) (int, error) {
+	return 0, errTransient
+}
+
+// TestRecover verifies that Recover substitutes the handler's value for a failure
+// and feeds directly into the next Async.
+func TestRecover(t *testing.T) {
+	var got error
+	recovered := Recover(Async[int](Fail, 1), func(err error) int {
+		got = err
+		return 10
+	})
+	sum := Async[int](Add, recovered, 1)
+	if val := sum.Get(); val != 11 {
+		t.Errorf("Expected 11, got %d", val)
+	}
+	if !errors.Is(got, errTransient) {
+		t.Errorf("Expected the handler to receive the failure, got %v", got)
+	}
+
+	if val := Recover(Async[int](Square, 3), func(error) int { return -1 }).Get(); val != 9 {
+		t.Errorf("Expected the value of a successful promise, got %d", val)
+	}
+}
+
+// TestOrElse verifies that OrElse falls back to the alternative promise only on failure.
+func TestOrElse(t *testing.T) {
+	if val := OrElse(Async[int](Fail, 1), Async[int](Square, 4)).Get(); val != 16 {
+		t.Errorf("Expected 16, got %d", val)
+	}
+	if val := OrElse(Async[int](Square, 2), Async[int](Fail, 1)).Get(); val != 4 {
+		t.Errorf("Expected 4, got %d", val)
+	}
+	if err := OrElse(Async[int](Fail, 1), Async[int](Fail, 2)).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the alternative's failure, got %v", err)
+	}
+}
+
+// TestDefault verifies that Default substitutes a value for a failure, and records the dependency.
+func TestDefault(t *testing.T) {
+	rec := StartRecording()
+	failed := Async[int](Fail, 1)
+	p := Default(failed, 7, WithName("fallback"))
+	if val := p.Get(); val != 7 {
+		t.Errorf("Expected 7, got %d", val)
+	}
+	rec.Stop()
+
+	found := false
+	for _, e := range rec.Graph().Edges {
+		found = found || (e.From == failed.id && e.To == p.id)
+	}
+	if !found {
+		t.Errorf("Expected an edge from the failed promise to the fallback")
+	}
+}
@@ -0,0 +1,108 @@
+package pas
+
+import (
+	"errors"
+	"reflect"
+)
+
+// errEmptyReduce rejects the reduction of an empty slice.
+var errEmptyReduce = errors.New("pas: reduction of an empty slice")
+
+// WithCommutative declares that the combine function of Reduce or MapReduce is commutative,
+// so that values are combined in the order they complete rather than in slice order.
+func WithCommutative() Option {
+	return optionFunc(func(c *config) {
+		c.commutative = true
+	})
+}
+
+// Reduce returns a Promise resolved with the combination of the values of ps.
+// combine must be associative: values are combined pairwise in a balanced binary tree
+// of Async calls, so that the tree has a depth of log2(len(ps)) rather than the len(ps) of a linear chain.
+// With WithCommutative, any two ready values are combined as soon as they are available instead.
+// The Options are applied to every combine call. If a Promise is rejected, the result is rejected with its error;
+// if ps is empty, the result is rejected.
+// Usage example: total := pas.Reduce(partialSums, func(a, b int) int { return a + b })
+func Reduce[T any](ps []*Promise[T], combine func(T, T) T, opts ...Option) *Promise[T] {
+	if len(ps) == 0 {
+		p := newPending[T]()
+		var zero T
+		p.settle(zero, errEmptyReduce)
+		return p
+	}
+	if newConfig(opts).commutative {
+		return reduceUnordered(ps, combine, opts)
+	}
+	return reduceTree(ps, combine, opts)
+}
+
+// reduceTree combines the values of ps in a balanced binary tree, preserving their order.
+func reduceTree[T any](ps []*Promise[T], combine func(T, T) T, opts []Option) *Promise[T] {
+	if len(ps) == 1 {
+		return ps[0]
+	}
+	mid := len(ps) / 2
+	left, right := reduceTree(ps[:mid], combine, opts), reduceTree(ps[mid:], combine, opts)
+	return Async[T](combine, withOptions([]interface{}{left, right}, opts)...)
+}
+
+// reduceUnordered combines the values of ps in the order they complete.
+// Whenever two values are ready, an Async call combines them, and its result joins the remaining values.
+func reduceUnordered[T any](ps []*Promise[T], combine func(T, T) T, opts []Option) *Promise[T] {
+	q := newPending[T]()
+	// Room for the values of ps and of every combine call, so that no watcher blocks once q is settled
+	ready := make(chan *Promise[T], 2*len(ps))
+	watch := func(p *Promise[T]) {
+		go func() {
+			<-p.ready
+			ready <- p
+		}()
+	}
+	for _, p := range ps {
+		watch(p)
+	}
+
+	go func() {
+		var held *Promise[T]
+		// remaining counts the values not yet combined, including those being computed
+		for remaining := len(ps); ; {
+			p := <-ready
+			if p.err != nil || remaining == 1 {
+				q.settle(p.value, p.err)
+				return
+			}
+			if held == nil {
+				held = p
+				continue
+			}
+			watch(Async[T](combine, withOptions([]interface{}{held, p}, opts)...))
+			held = nil
+			remaining--
+		}
+	}()
+	return q
+}
+
+// MapReduce returns a Promise resolved with the combination of mapper applied to every element of in,
+// which is either a []T or a []*Promise[T].
+// Elements are split into chunks like in ParallelMap; each chunk maps and combines its own elements in order,
+// without storing the mapped values, and the chunks' results are then combined like in Reduce.
+// Usage example: words := pas.MapReduce(lines, CountWords, func(a, b int) int { return a + b })
+func MapReduce[T, U any](in interface{}, mapper func(T) U, combine func(U, U) U, opts ...Option) *Promise[U] {
+	n, get := sliceSource[T](in)
+	chunks := startChunks("MapReduce", n, reflect.ValueOf(mapper), newConfig(opts), func(t *task, lo, hi int) (acc U, err error) {
+		for i := lo; i < hi; i++ {
+			value, err := get(t, i)
+			if err != nil {
+				return acc, err
+			}
+			if i == lo {
+				acc = mapper(value)
+			} else {
+				acc = combine(acc, mapper(value))
+			}
+		}
+		return acc, nil
+	})
+	return Reduce(chunks, combine, opts...)
+}
@@ -0,0 +1,65 @@
+package pas
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestReduceTree verifies that the balanced tree preserves the order of a non-commutative combine,
+// and that its depth is logarithmic.
+func TestReduceTree(t *testing.T) {
+	letters := MakeSlice[string](0, 9)
+	for _, l := range "abcdefghi" {
+		letters = append(letters, Async[string](func(s string) string { return s }, string(l)))
+	}
+	rec := StartRecording()
+	concat := Reduce(letters, func(a, b string) string { return a + b })
+	if val := concat.Get(); val != "abcdefghi" {
+		t.Errorf("Expected abcdefghi, got %q", val)
+	}
+	rec.Stop()
+	if report := rec.Analyze(); len(report.CriticalPath) > 5 {
+		t.Errorf("Expected a critical path of at most 5 tasks, got %d", len(report.CriticalPath))
+	}
+
+	if err := Reduce([]*Promise[int]{}, Add).Err(); err == nil {
+		t.Errorf("Expected an empty reduction to be rejected")
+	}
+}
+
+// TestReduceUnordered verifies the completion-order reduction, including rejections.
+func TestReduceUnordered(t *testing.T) {
+	ps := make([]*Promise[int], 20)
+	for i := range ps {
+		ps[i] = Async[int](SleepContext, 20-i)
+	}
+	expected := 0
+	for i := 1; i <= 20; i++ {
+		expected += i
+	}
+	if val := Reduce(ps, func(a, b int) int { return a + b }, WithCommutative()).Get(); val != expected {
+		t.Errorf("Expected %d, got %d", expected, val)
+	}
+	if val := Reduce(ps[:1], Add, WithCommutative()).Get(); val != 20 {
+		t.Errorf("Expected 20, got %d", val)
+	}
+
+	ps[7] = Async[int](Fail, 1)
+	if err := Reduce(ps, func(a, b int) int { return a + b }, WithCommutative()).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the rejection of an element, got %v", err)
+	}
+}
+
+// TestMapReduce verifies the fused map and reduction over values and promises.
+func TestMapReduce(t *testing.T) {
+	words := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	length := func(s string) int { return len(s) }
+	sum := func(a, b int) int { return a + b }
+	if val := MapReduce(words, length, sum, WithGrain(2)).Get(); val != 15 {
+		t.Errorf("Expected 15, got %d", val)
+	}
+	promises := []*Promise[string]{New("x"), Async[string](func() string { return "yz" })}
+	if val := MapReduce(promises, length, sum, WithCommutative()).Get(); val != 3 {
+		t.Errorf("Expected 3, got %d", val)
+	}
+}
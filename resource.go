@@ -0,0 +1,157 @@
+package pas
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// resources is the registry of the resources defined with DefineResource, by name.
+var resources = struct {
+	sync.Mutex
+	byName map[string]*Resource
+}{byName: make(map[string]*Resource)}
+
+// Resource is a named pool of a scarce resource, such as database connections or memory,
+// shared by the tasks declaring their needs with WithResource.
+// A task does not start until all the units it needs are available, and releases them once it finishes.
+// Waiting tasks are served in arrival order, so that tasks needing many units are not starved.
+type Resource struct {
+	name string
+
+	mu       sync.Mutex
+	capacity int
+	inUse    int
+	waiters  []*resourceWaiter
+}
+
+// resourceWaiter is a task waiting for units of a Resource.
+type resourceWaiter struct {
+	n     int
+	ready chan struct{}
+}
+
+// resourceNeed is a number of units of a Resource needed by a task.
+type resourceNeed struct {
+	res *Resource
+	n   int
+}
+
+// DefineResource defines the resource with the given name and capacity, and returns it.
+// Defining an existing resource again changes its capacity.
+// Usage example:
+// pas.DefineResource("db", 4)
+// p := pas.Async[Rows](Query, sql, pas.WithResource("db", 1))
+func DefineResource(name string, capacity int) *Resource {
+	resources.Lock()
+	defer resources.Unlock()
+	r, ok := resources.byName[name]
+	if !ok {
+		r = &Resource{name: name}
+		resources.byName[name] = r
+	}
+	r.mu.Lock()
+	r.capacity = capacity
+	r.wake()
+	r.mu.Unlock()
+	return r
+}
+
+// WithResource makes the task hold n units of the named resource while it runs.
+// The resource must have been defined with DefineResource, with a capacity of at least n.
+// Resources are acquired after the task's arguments are resolved, so that tasks waiting for their arguments
+// do not hold them, and before a worker of its Executor, if any. Each attempt acquires and releases them;
+// an attempt exceeding its WithTimeout budget releases them only once its function returns.
+func WithResource(name string, n int) Option {
+	resources.Lock()
+	r, ok := resources.byName[name]
+	resources.Unlock()
+	if !ok {
+		panic(fmt.Sprintf("pas.WithResource: undefined resource %q", name))
+	}
+	if n > r.Capacity() {
+		panic(fmt.Sprintf("pas.WithResource: %d units of resource %q exceed its capacity of %d", n, name, r.Capacity()))
+	}
+	return optionFunc(func(c *config) {
+		for i := range c.resources {
+			if c.resources[i].res == r {
+				c.resources[i].n = n
+				return
+			}
+		}
+		c.resources = append(c.resources, resourceNeed{r, n})
+	})
+}
+
+// Name returns the name of the resource.
+func (r *Resource) Name() string {
+	return r.name
+}
+
+// Capacity returns the number of units of the resource.
+func (r *Resource) Capacity() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.capacity
+}
+
+// InUse returns the number of units held by running tasks.
+func (r *Resource) InUse() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.inUse
+}
+
+// Waiting returns the number of tasks waiting for units of the resource.
+func (r *Resource) Waiting() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.waiters)
+}
+
+// acquire waits until n units of the resource are available, and takes them.
+func (r *Resource) acquire(n int) {
+	r.mu.Lock()
+	if len(r.waiters) == 0 && r.inUse+n <= r.capacity {
+		r.inUse += n
+		r.mu.Unlock()
+		return
+	}
+	w := &resourceWaiter{n: n, ready: make(chan struct{})}
+	r.waiters = append(r.waiters, w)
+	r.mu.Unlock()
+	<-w.ready
+}
+
+// release returns n units of the resource, and wakes the waiters they allow to proceed.
+func (r *Resource) release(n int) {
+	r.mu.Lock()
+	r.inUse -= n
+	r.wake()
+	r.mu.Unlock()
+}
+
+// wake hands the available units to the waiters in arrival order. The resource must be locked.
+func (r *Resource) wake() {
+	for len(r.waiters) > 0 && r.inUse+r.waiters[0].n <= r.capacity {
+		w := r.waiters[0]
+		r.waiters = r.waiters[1:]
+		r.inUse += w.n
+		close(w.ready)
+	}
+}
+
+// acquireResources takes the units of every resource needed by a task, and returns the function releasing them.
+// Resources are always acquired in the order of their names, so that tasks needing several cannot deadlock.
+func acquireResources(needs []resourceNeed) func() {
+	sorted := append([]resourceNeed(nil), needs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].res.name < sorted[j].res.name })
+	for _, need := range sorted {
+		need.res.acquire(need.n)
+	}
+	return func() {
+		for _, need := range sorted {
+			need.res.release(need.n)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package pas
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestResourceLimit verifies that tasks never hold more units of a resource than its capacity,
+// including tasks needing several resources.
+func TestResourceLimit(t *testing.T) {
+	DefineResource("test-conn", 2)
+	DefineResource("test-mem", 3)
+	var conns, maxConns atomic.Int32
+	query := func(n int) int {
+		c := conns.Add(1)
+		for m := maxConns.Load(); c > m && !maxConns.CompareAndSwap(m, c); m = maxConns.Load() {
+		}
+		time.Sleep(5 * time.Millisecond)
+		conns.Add(-1)
+		return n
+	}
+	ps := make([]*Promise[int], 10)
+	for i := range ps {
+		ps[i] = Async[int](query, i, WithResource("test-mem", 1+i%3), WithResource("test-conn", 1))
+	}
+	for i, p := range ps {
+		if val := p.Get(); val != i {
+			t.Errorf("Expected %d, got %d", i, val)
+		}
+	}
+	if m := maxConns.Load(); m > 2 {
+		t.Errorf("Expected at most 2 tasks holding a connection, got %d", m)
+	}
+	if r := DefineResource("test-conn", 2); r.InUse() != 0 || r.Waiting() != 0 {
+		t.Errorf("Expected all units to be released, got %d in use and %d waiting", r.InUse(), r.Waiting())
+	}
+}
+
+// TestResourceAfterArguments verifies that a task waiting for its arguments does not hold its resources.
+func TestResourceAfterArguments(t *testing.T) {
+	DefineResource("test-single", 1)
+	release := make(chan struct{})
+	slowArg := Async[int](func() int {
+		<-release
+		return 1
+	})
+	waiting := Async[int](Add, slowArg, 1, WithResource("test-single", 1))
+	if val := Async[int](Square, 3, WithResource("test-single", 1)).Timeout(time.Second).Get(); val != 9 {
+		t.Errorf("Expected 9, got %d", val)
+	}
+	close(release)
+	if val := waiting.Get(); val != 2 {
+		t.Errorf("Expected 2, got %d", val)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected WithResource to panic for an undefined resource")
+		}
+	}()
+	WithResource("test-undefined", 1)
+}
+
+// TestResourceTimeout verifies that a task exceeding its budget keeps its resources, and its Executor worker,
+// until its function returns.
+func TestResourceTimeout(t *testing.T) {
+	DefineResource("test-handle", 1)
+	e := NewExecutor(1)
+	var withResource, withWorker gauge
+	slow := func(g *gauge) int {
+		defer g.enter()()
+		time.Sleep(30 * time.Millisecond)
+		return 1
+	}
+	var ps []*Promise[int]
+	for i := 0; i < 3; i++ {
+		ps = append(ps,
+			Async[int](slow, &withResource, WithResource("test-handle", 1), WithTimeout(5*time.Millisecond)),
+			Async[int](slow, &withWorker, WithExecutor(e), WithTimeout(5*time.Millisecond)))
+	}
+	for _, p := range ps {
+		if err := p.Err(); !errors.Is(err, ErrTimeout) {
+			t.Errorf("Expected a timeout, got %v", err)
+		}
+	}
+	if m := withResource.max.Load(); m != 1 {
+		t.Errorf("Expected 1 task at once holding the resource, got %d", m)
+	}
+	if m := withWorker.max.Load(); m != 1 {
+		t.Errorf("Expected 1 task at once on the Executor, got %d", m)
+	}
+}
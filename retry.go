@@ -0,0 +1,60 @@
+package pas
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy describes how a failed task is retried.
+// A task fails when its function panics, returns a non-nil error as its second value,
+// or exceeds its execution budget. Rejected arguments and wait timeouts are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the delay grows after each attempt. Zero means 2.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction of it, in either direction, between 0 and 1.
+	Jitter float64
+	// Retryable decides whether a failed attempt is retried. Panics are passed as a *PanicError.
+	// A nil Retryable retries every failure.
+	Retryable func(err error) bool
+}
+
+// WithRetry retries the task according to policy when it fails.
+// Each attempt reuses the already-resolved arguments, and is reported to observers with its TaskInfo.Attempt.
+// The execution budget set by WithTimeout applies to each attempt. An attempt exceeding it is followed by the next one
+// only once its function returns, so that attempts never overlap; the function should return when its context is cancelled.
+func WithRetry(policy RetryPolicy) Option {
+	return optionFunc(func(c *config) {
+		c.retry = &policy
+	})
+}
+
+// shouldRetry reports whether another attempt should follow the given failed one.
+func (p *RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// backoff returns the delay after the given failed attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
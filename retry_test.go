@@ -0,0 +1,118 @@
+package pas
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+// flaky returns a function that fails the given number of times before returning its argument doubled.
+func flaky(failures int32, calls *atomic.Int32) func(int) (int, error) {
+	return func(n int) (int, error) {
+		if calls.Add(1) <= failures {
+			return 0, errTransient
+		}
+		return 2 * n, nil
+	}
+}
+
+// TestRetrySucceeds verifies that a failing task is retried with its resolved arguments,
+// and that every attempt is reported to observers.
+func TestRetrySucceeds(t *testing.T) {
+	var calls atomic.Int32
+	obs := newCountingObserver()
+	arg := Async[int](Square, 3)
+	p := Async[int](flaky(2, &calls), arg, WithObserver(obs),
+		WithRetry(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond, Jitter: 0.5}))
+
+	if val, err := p.Result(); err != nil || val != 18 {
+		t.Fatalf("Expected 18, got %d (%v)", val, err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected 3 calls, got %d", n)
+	}
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if obs.events["start"] != 3 || obs.events["finish"] != 3 || obs.events["resolved"] != 1 {
+		t.Errorf("Expected 3 attempts after resolving once, got %v", obs.events)
+	}
+	if len(obs.errs) != 2 {
+		t.Errorf("Expected 2 failed attempts, got %d", len(obs.errs))
+	}
+}
+
+// TestRetryGivesUp verifies the attempt limit and the retryable predicate.
+func TestRetryGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	p := Async[int](flaky(10, &calls), 1, WithRetry(RetryPolicy{MaxAttempts: 3}))
+	if err := p.Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected transient error, got %v", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected 3 calls, got %d", n)
+	}
+
+	SetObserver(nil)
+	defer SetObserver(LogObserver{})
+	var panics atomic.Int32
+	explode := func(n int) int {
+		panics.Add(1)
+		panic("boom")
+	}
+	onlyTransient := func(err error) bool { return errors.Is(err, errTransient) }
+	q := Async[int](explode, 1, WithRetry(RetryPolicy{MaxAttempts: 3, Retryable: onlyTransient}))
+	var pe *PanicError
+	if err := q.Err(); !errors.As(err, &pe) {
+		t.Errorf("Expected PanicError, got %v", err)
+	}
+	if n := panics.Load(); n != 1 {
+		t.Errorf("Expected the panic not to be retried, got %d calls", n)
+	}
+}
+
+// gauge tracks the number of functions running at once, and its maximum.
+type gauge struct {
+	running, max atomic.Int32
+}
+
+// enter records the start of a function, and returns the function recording its end.
+func (g *gauge) enter() func() {
+	r := g.running.Add(1)
+	for m := g.max.Load(); r > m && !g.max.CompareAndSwap(m, r); m = g.max.Load() {
+	}
+	return func() { g.running.Add(-1) }
+}
+
+// TestRetryTimeoutOverlap verifies that an attempt exceeding its budget is not followed
+// by the next one before its function returns.
+func TestRetryTimeoutOverlap(t *testing.T) {
+	var g gauge
+	var calls atomic.Int32
+	slow := func() (int, error) {
+		defer g.enter()()
+		if calls.Add(1) < 3 {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return 1, nil
+	}
+	p := Async[int](slow, WithTimeout(5*time.Millisecond), WithRetry(RetryPolicy{MaxAttempts: 3}))
+	if val, err := p.Result(); err != nil || val != 1 {
+		t.Fatalf("Expected 1, got %d (%v)", val, err)
+	}
+	if m := g.max.Load(); m != 1 {
+		t.Errorf("Expected attempts not to overlap, got %d at once", m)
+	}
+}
+
+// TestRetryBackoff verifies exponential growth and capping of the backoff.
+func TestRetryBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt, expected := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 4: 50 * time.Millisecond} {
+		if d := p.backoff(attempt); d != expected {
+			t.Errorf("Expected backoff %v after attempt %d, got %v", expected, attempt, d)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package pas
+
+import "sync"
+
+// Strand runs the tasks assigned to it with WithStrand one at a time, in the order they were submitted,
+// for functions touching state that is not safe for concurrent use, such as a database handle.
+// Each task still resolves its arguments concurrently with the others, and only waits for its turn
+// once they are ready, so it does not hold up unrelated work. Tasks on different Strands, and tasks
+// on no Strand, run in parallel. A task holds its turn until it is done, including across retries,
+// and until its function returns if it exceeded its WithTimeout budget.
+// A task awaiting the Promise of a later task of the same Strand, or calling Sync on its own Strand, deadlocks.
+// Usage example:
+// db := pas.NewStrand()
+// pas.Async[int](Insert, conn, row, pas.WithStrand(db))
+// n := pas.Async[int](Count, conn, pas.WithStrand(db))
+type Strand struct {
+	mu      sync.Mutex
+	last    *strandTurn // Turn of the last submitted task, or nil once all are done
+	pending int
+}
+
+// strandTurn is the turn of a task on its Strand.
+type strandTurn struct {
+	strand   *Strand
+	task     *task
+	prev     *task         // The previous task of the Strand, until the turn is acquired
+	prevDone chan struct{} // Closed once the previous task is done
+	done     chan struct{}
+}
+
+// NewStrand creates a Strand.
+func NewStrand() *Strand {
+	return &Strand{}
+}
+
+// WithStrand runs the task on s, after all the tasks submitted to s before it, and before those submitted after it.
+func WithStrand(s *Strand) Option {
+	return optionFunc(func(c *config) {
+		c.strand = s
+	})
+}
+
+// Pending returns the number of tasks submitted to the Strand that are not done yet.
+func (s *Strand) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// reserve queues a turn for t after the tasks already submitted to the Strand.
+func (s *Strand) reserve(t *task) *strandTurn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	turn := &strandTurn{strand: s, task: t, done: make(chan struct{})}
+	if s.last != nil {
+		turn.prev, turn.prevDone = s.last.task, s.last.done
+	}
+	s.last = turn
+	s.pending++
+	return turn
+}
+
+// wait blocks until the previous task of the Strand is done.
+// Meanwhile, t is recorded as waiting on it, so that it inherits the priority of t.
+func (turn *strandTurn) wait(t *task) {
+	if turn.prev == nil {
+		return
+	}
+	t.sched.waitFor(turn.prev)
+	turn.prev.inheritPriority(t.sched.get())
+	<-turn.prevDone
+	t.sched.waitFor(nil)
+	turn.prev, turn.prevDone = nil, nil
+}
+
+// release ends the turn, letting the next task of the Strand run once detached is closed, if not nil.
+// A task done without taking its turn, such as one whose arguments were rejected,
+// passes it on once the previous task is done, so that later tasks still run in order.
+// A task whose function exceeded its budget passes it on once the function returns.
+func (turn *strandTurn) release(detached <-chan struct{}) {
+	if prevDone := turn.prevDone; prevDone != nil {
+		turn.prev, turn.prevDone = nil, nil
+		go func() {
+			<-prevDone
+			turn.release(detached)
+		}()
+		return
+	}
+	if detached != nil {
+		afterReturn(detached, func() { turn.release(nil) })
+		return
+	}
+	s := turn.strand
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(turn.done)
+	s.pending--
+	if s.last == turn {
+		s.last = nil
+	}
+}
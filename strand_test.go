@@ -0,0 +1,163 @@
+package pas
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStrandOrder verifies that the tasks of a Strand run one at a time in submission order,
+// even when the arguments of later tasks are ready first.
+func TestStrandOrder(t *testing.T) {
+	s := NewStrand()
+	var mu sync.Mutex
+	var order []int
+	var running, maxRunning atomic.Int32
+	record := func(i int) int {
+		r := running.Add(1)
+		for m := maxRunning.Load(); r > m && !maxRunning.CompareAndSwap(m, r); m = maxRunning.Load() {
+		}
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		order = append(order, i)
+		mu.Unlock()
+		running.Add(-1)
+		return i
+	}
+	delayed := func(i int) int {
+		time.Sleep(time.Duration(10-i) * time.Millisecond)
+		return i
+	}
+
+	ps := make([]*Promise[int], 10)
+	for i := range ps {
+		ps[i] = Async[int](record, Async[int](delayed, i), WithStrand(s))
+	}
+	for i, p := range ps {
+		if val := p.Get(); val != i {
+			t.Errorf("Expected %d, got %d", i, val)
+		}
+	}
+	for i, val := range order {
+		if val != i {
+			t.Fatalf("Expected the tasks to run in submission order, got %v", order)
+		}
+	}
+	if m := maxRunning.Load(); m != 1 {
+		t.Errorf("Expected tasks to run one at a time, got %d at once", m)
+	}
+	if n := s.Pending(); n != 0 {
+		t.Errorf("Expected no pending tasks, got %d", n)
+	}
+}
+
+// TestStrandParallel verifies that tasks on different Strands and on no Strand run in parallel.
+func TestStrandParallel(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(3)
+	all := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(all)
+	}()
+	meet := func() bool {
+		wg.Done()
+		select {
+		case <-all:
+			return true
+		case <-time.After(time.Second):
+			return false
+		}
+	}
+
+	ps := []*Promise[bool]{
+		Async[bool](meet, WithStrand(NewStrand())),
+		Async[bool](meet, WithStrand(NewStrand())),
+		Async[bool](meet),
+	}
+	for i, p := range ps {
+		if !p.Get() {
+			t.Errorf("Expected task %d to run alongside the others", i)
+		}
+	}
+}
+
+// TestStrandRejected verifies that a task whose arguments are rejected does not let the next task
+// of its Strand run before the previous one is done.
+func TestStrandRejected(t *testing.T) {
+	s := NewStrand()
+	release := make(chan struct{})
+	var firstDone atomic.Bool
+	first := Async[int](func() int {
+		<-release
+		firstDone.Store(true)
+		return 1
+	}, WithStrand(s))
+	rejected := Async[int](Add, Async[int](Fail, 0), 1, WithStrand(s))
+	third := Async[bool](firstDone.Load, WithStrand(s))
+
+	if _, err := rejected.Result(); err == nil {
+		t.Errorf("Expected the second task to be rejected")
+	}
+	close(release)
+	if first.Get() != 1 || !third.Get() {
+		t.Errorf("Expected the third task to run after the first one")
+	}
+}
+
+// TestStrandTimeout verifies that a task exceeding its budget keeps its turn until its function returns.
+func TestStrandTimeout(t *testing.T) {
+	s := NewStrand()
+	var g gauge
+	slow := func() int {
+		defer g.enter()()
+		time.Sleep(30 * time.Millisecond)
+		return 1
+	}
+	ps := make([]*Promise[int], 3)
+	for i := range ps {
+		ps[i] = Async[int](slow, WithStrand(s), WithTimeout(5*time.Millisecond))
+	}
+	for _, p := range ps {
+		if err := p.Err(); !errors.Is(err, ErrTimeout) {
+			t.Errorf("Expected a timeout, got %v", err)
+		}
+	}
+	if m := g.max.Load(); m != 1 {
+		t.Errorf("Expected tasks to run one at a time, got %d at once", m)
+	}
+}
+
+// TestStrandContinuation verifies that fallbacks and chunks on a Strand wait for their turn.
+func TestStrandContinuation(t *testing.T) {
+	s := NewStrand()
+	release := make(chan struct{})
+	var firstDone atomic.Bool
+	Async[int](func() int {
+		<-release
+		firstDone.Store(true)
+		return 1
+	}, WithStrand(s))
+	recovered := Recover(Async[int](Fail, 0), func(error) int {
+		if firstDone.Load() {
+			return 1
+		}
+		return 0
+	}, WithStrand(s))
+	var inOrder atomic.Bool
+	go ParallelFor(1, func(int) { inOrder.Store(firstDone.Load()) }, WithStrand(s))
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	if recovered.Get() != 1 {
+		t.Errorf("Expected the fallback to run after the first task")
+	}
+	for s.Pending() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if !inOrder.Load() {
+		t.Errorf("Expected the chunk to run after the first task")
+	}
+}
@@ -0,0 +1,179 @@
+package pas
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Stream is a sequence of values produced incrementally by a task started with AsyncStream.
+// A Stream has a single consumer: receive its values from C, collect them with Collect,
+// or pass the Stream to Async for a parameter of type []T (all the values) or <-chan T (each value as it arrives).
+// An error ending the Stream rejects the tasks it is passed to; for a channel, once the task's function returns.
+// A consumer that stops reading early calls Cancel, so that the producer stops too.
+type Stream[T any] struct {
+	ch      chan T
+	done    *Promise[struct{}] // Settled once the producer returns
+	ctx     context.Context    // Cancelled once the consumer is gone, or the producer returns
+	cancel  context.CancelFunc
+	collect sync.Once
+	all     *Promise[[]T]
+}
+
+// Sink is where the function started by AsyncStream emits its values.
+type Sink[T any] struct {
+	s *Stream[T]
+}
+
+// streamContract is implemented by Streams, to pass them as arguments of tasks.
+type streamContract interface {
+	collected() promiseTypeContract
+	elements() reflect.Value
+	failure() error
+	Cancel()
+}
+
+// WithBuffer sets the number of values a Stream holds before Sink.Emit blocks, waiting for the consumer.
+// The default is 0: each value is handed over to the consumer directly.
+func WithBuffer(n int) Option {
+	return optionFunc(func(c *config) {
+		c.buffer = n
+	})
+}
+
+// AsyncStream starts a task producing a Stream by invoking function f with a Sink and the provided arguments.
+// f takes the *Sink[T] as its first parameter, after an optional context.Context which is then always injected,
+// and must return an error. It emits values with Sink.Emit, and the Stream ends when f returns.
+// A non-nil error, a panic, or a rejected argument ends the Stream with that error.
+// Arguments are resolved like for Async, and the same Options apply, as well as WithBuffer;
+// WithRetry, WithMemo, WithDiskCache and WithCheckpoint are not supported, and panic.
+// Once the Stream is cancelled, Emit returns an error and the context passed to f is cancelled.
+// Usage example:
+// s := pas.AsyncStream[Result](ProcessFiles, paths, pas.WithBuffer(8))
+// func ProcessFiles(sink *pas.Sink[Result], paths []string) error { ... sink.Emit(r) ... }
+func AsyncStream[T any](f interface{}, args ...interface{}) *Stream[T] {
+	return startStream[T](context.Background(), f, args)
+}
+
+// startStream is AsyncStream, with the context of the Stream derived from ctx.
+func startStream[T any](ctx context.Context, f interface{}, args []interface{}) *Stream[T] {
+	s := &Stream[T]{}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	fv, args, recursive, cfg, injectCtx := parseCall("AsyncStream", f, append([]interface{}{&Sink[T]{s}}, args...))
+	// Values already emitted cannot be taken back, so the function can neither be called again nor skipped
+	cfg.rejectOptions("AsyncStream", "WithRetry", "WithMemo", "WithDiskCache", "WithCheckpoint")
+	s.ch = make(chan T, cfg.buffer)
+
+	p := newPending[struct{}]()
+	t := newTask(p.id, fv, len(args), false, cfg)
+	t.ctx = s.ctx
+	t.injectCtx = injectCtx
+	p.task = t
+	s.done = p
+
+	go func() {
+		defer s.cancel()
+		output, err := executeFunction[error](t, f, recursive, args...)
+		if err == nil {
+			err = output
+		}
+		// Settle before closing, so that a consumer seeing the channel closed can check the error without waiting
+		p.settle(struct{}{}, err)
+		close(s.ch)
+	}()
+	return s
+}
+
+// Emit sends a value to the Stream's consumer, blocking while the Stream's buffer is full.
+// It returns the error of the Stream's context without sending the value once the Stream is cancelled;
+// the producing function should then return.
+// It must not be called after the producing function has returned.
+func (k *Sink[T]) Emit(v T) error {
+	if err := k.s.ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case k.s.ch <- v:
+		return nil
+	case <-k.s.ctx.Done():
+		return k.s.ctx.Err()
+	}
+}
+
+// Cancel tells the producer of the Stream that its values are no longer needed, for a consumer stopping early:
+// Emit returns an error from then on, and the context of the producing function is cancelled.
+// The Stream then ends with the error its function returns. Cancelling a Stream that ended has no effect.
+func (s *Stream[T]) Cancel() {
+	s.cancel()
+}
+
+// C returns the channel receiving the values of the Stream, which is closed when the Stream ends.
+// Check Err once it is closed to tell whether the Stream ended with an error.
+func (s *Stream[T]) C() <-chan T {
+	return s.ch
+}
+
+// Err waits for the Stream to end, and returns the error it ended with, or nil.
+// The values must be consumed for the Stream to end, unless they fit in its buffer.
+func (s *Stream[T]) Err() error {
+	return s.done.Err()
+}
+
+// Collect returns a Promise resolved with all the values of the Stream once it ends,
+// or rejected with the error the Stream ended with.
+// Repeated calls return the same Promise.
+func (s *Stream[T]) Collect() *Promise[[]T] {
+	s.collect.Do(func() {
+		s.all = newPending[[]T]()
+		go func() {
+			var values []T
+			for v := range s.ch {
+				values = append(values, v)
+			}
+			s.all.settle(values, s.done.Err())
+		}()
+	})
+	return s.all
+}
+
+// collected returns the Promise of all the values of the Stream.
+func (s *Stream[T]) collected() promiseTypeContract {
+	return s.Collect()
+}
+
+// elements returns the channel receiving the values of the Stream.
+func (s *Stream[T]) elements() reflect.Value {
+	return reflect.ValueOf(s.ch)
+}
+
+// failure returns the error the Stream ended with, or nil if it succeeded or has not ended yet.
+func (s *Stream[T]) failure() error {
+	select {
+	case <-s.done.ready:
+		return s.done.err
+	default:
+		return nil
+	}
+}
+
+// resolveStream resolves a Stream passed as an argument of a task, for a parameter of the expected type:
+// a slice receives all the values, once the Stream ends, and a channel receives each value as it arrives.
+// For a channel, the error the Stream ends with is checked once the task's function returns,
+// and the Stream is then cancelled, in case the function did not read it to the end.
+func resolveStream(t *task, s streamContract, expectedType reflect.Type) (interface{}, error) {
+	switch expectedType.Kind() {
+	case reflect.Slice:
+		return t.await(s.collected())
+	case reflect.Chan:
+		ch := s.elements()
+		if !ch.Type().ConvertibleTo(expectedType) {
+			return nil, fmt.Errorf("cannot pass a stream of %s as %s", ch.Type().Elem(), expectedType)
+		}
+		t.lateArgs = append(t.lateArgs, s.failure)
+		t.cleanups = append(t.cleanups, s.Cancel)
+		return ch.Convert(expectedType).Interface(), nil
+	default:
+		return nil, fmt.Errorf("a stream can only be passed as a slice or a channel, not as %s", expectedType)
+	}
+}
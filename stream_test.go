@@ -0,0 +1,140 @@
+package pas
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countTo emits the numbers from 1 to n, and fails with fail if it is not nil.
+func countTo(sink *Sink[int], n int, fail error) error {
+	for i := 1; i <= n; i++ {
+		sink.Emit(i)
+	}
+	return fail
+}
+
+// TestStreamConsumers verifies that a stream can be consumed as a whole or element by element by Async.
+func TestStreamConsumers(t *testing.T) {
+	all := AsyncStream[int](countTo, Async[int](Add, 2, 3), nil)
+	if val := Async[int](SumSlice, all).Get(); val != 15 {
+		t.Errorf("Expected 15, got %d", val)
+	}
+
+	sumChan := func(ch <-chan int) int {
+		sum := 0
+		for n := range ch {
+			sum += n
+		}
+		return sum
+	}
+	each := AsyncStream[int](countTo, 4, nil, WithBuffer(2))
+	if val := Async[int](sumChan, each).Get(); val != 10 {
+		t.Errorf("Expected 10, got %d", val)
+	}
+	if err := each.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// TestStreamError verifies that an error ends the stream and rejects its collection and consumers.
+func TestStreamError(t *testing.T) {
+	s := AsyncStream[int](countTo, 2, errTransient)
+	var got []int
+	for n := range s.C() {
+		got = append(got, n)
+	}
+	if len(got) != 2 || !errors.Is(s.Err(), errTransient) {
+		t.Errorf("Expected 2 values and a transient error, got %v and %v", got, s.Err())
+	}
+
+	failed := AsyncStream[int](countTo, 3, errTransient)
+	if err := Async[int](SumSlice, failed).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the consumer to be rejected, got %v", err)
+	}
+	drain := func(ch <-chan int) int {
+		n := 0
+		for range ch {
+			n++
+		}
+		return n
+	}
+	if err := Async[int](drain, AsyncStream[int](countTo, 3, errTransient)).Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the channel consumer to be rejected, got %v", err)
+	}
+	if err := AsyncStream[int](countTo, Async[int](Fail, 1), nil).Collect().Err(); !errors.Is(err, errTransient) {
+		t.Errorf("Expected the rejected argument to end the stream, got %v", err)
+	}
+}
+
+// TestStreamBackpressure verifies that the producer blocks once the buffer is full.
+func TestStreamBackpressure(t *testing.T) {
+	var emitted atomic.Int32
+	produce := func(sink *Sink[int]) error {
+		for i := 0; i < 10; i++ {
+			sink.Emit(i)
+			emitted.Add(1)
+		}
+		return nil
+	}
+	s := AsyncStream[int](produce, WithBuffer(3))
+	time.Sleep(20 * time.Millisecond)
+	if n := emitted.Load(); n != 3 {
+		t.Errorf("Expected the producer to block after 3 values, got %d", n)
+	}
+	if values := s.Collect().Get(); len(values) != 10 {
+		t.Errorf("Expected 10 values, got %v", values)
+	}
+}
+
+// countForever emits increasing numbers until the stream is cancelled.
+func countForever(sink *Sink[int]) error {
+	for i := 0; ; i++ {
+		if err := sink.Emit(i); err != nil {
+			return err
+		}
+	}
+}
+
+// TestStreamCancel verifies that cancelling a stream, or returning from a task consuming it as a channel,
+// stops its producer, and that the Options a stream cannot honour are rejected.
+func TestStreamCancel(t *testing.T) {
+	s := AsyncStream[int](countForever)
+	for i := 0; i < 3; i++ {
+		<-s.C()
+	}
+	s.Cancel()
+	if err := s.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	waitCancel := func(ctx context.Context, sink *Sink[int]) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	s = AsyncStream[int](waitCancel)
+	s.Cancel()
+	if err := s.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the injected context to be cancelled, got %v", err)
+	}
+
+	firstTwo := func(ch <-chan int) int {
+		return <-ch + <-ch
+	}
+	s = AsyncStream[int](countForever)
+	if val := Async[int](firstTwo, s).Get(); val != 1 {
+		t.Errorf("Expected 1, got %d", val)
+	}
+	if err := s.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the stream to be cancelled once its consumer returned, got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected WithRetry to be rejected")
+		}
+	}()
+	AsyncStream[int](countTo, 3, nil, WithRetry(RetryPolicy{MaxAttempts: 2}))
+}
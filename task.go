@@ -0,0 +1,453 @@
+package pas
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// lastID is the source of unique identifiers for promises and tasks.
+var lastID atomic.Uint64
+
+// nextID returns a new unique identifier.
+func nextID() uint64 {
+	return lastID.Add(1)
+}
+
+// task holds the bookkeeping for a single Async or Sync invocation.
+// For Async, the task shares its id with the Promise it produces.
+type task struct {
+	id       uint64
+	name     string
+	site     string
+	fn       string
+	numArgs  int
+	sync     bool
+	observer Observer
+	logger   *slog.Logger
+
+	ctx         context.Context // Parent of the context passed to the function
+	injectCtx   bool            // Whether a context is passed to the function as its first argument
+	timeout     time.Duration   // Execution budget, or 0
+	waitTimeout time.Duration   // Budget for waiting on arguments, or 0
+	waitCtx     context.Context
+	waitCancel  context.CancelFunc
+	retry       *RetryPolicy
+	memo        *Memo
+	executor    *Executor
+	sched       *schedState
+	resources   []resourceNeed
+	limiter     *RateLimiter
+	turn        *strandTurn    // Turn on the task's Strand, or nil
+	lateArgs    []func() error // Failures of channel arguments, only known once the function has consumed them
+	cleanups    []func()       // Called once the task is done and its function has returned, such as to stop producers
+
+	diskCache        *DiskCache
+	diskCacheVersion string
+	checkpoint       *Checkpoint
+
+	attempt      int             // Number of attempts started so far
+	attemptEnded bool            // Whether the last attempt has been reported to observers
+	detached     <-chan struct{} // Closed once the function of the last attempt returns, if it timed out, or nil
+	throttled    time.Duration   // Time the last attempt waited for RateLimiters
+
+	created      time.Time
+	argsResolved time.Time
+	started      time.Time
+	finished     time.Time
+}
+
+// newTask creates a task for invoking fn with numArgs arguments, and notifies its observers.
+func newTask(id uint64, fn reflect.Value, numArgs int, sync bool, cfg *config) *task {
+	t := &task{
+		id:       id,
+		name:     cfg.name,
+		site:     callerSite(),
+		fn:       funcName(fn),
+		numArgs:  numArgs,
+		sync:     sync,
+		observer: observerFor(cfg),
+		logger:   loggerFor(cfg),
+		created:  time.Now(),
+		ctx:      context.Background(),
+
+		timeout:     cfg.timeout,
+		waitTimeout: cfg.waitTimeout,
+		retry:       cfg.retry,
+		memo:        cfg.memo,
+		executor:    cfg.executor,
+		sched:       &schedState{priority: cfg.priority},
+		resources:   cfg.resources,
+		limiter:     cfg.limiter,
+
+		diskCache:        cfg.diskCache,
+		diskCacheVersion: cfg.diskCacheVersion,
+		checkpoint:       cfg.checkpoint,
+	}
+	if cfg.strand != nil {
+		t.turn = cfg.strand.reserve(t)
+	}
+	if t.waitTimeout > 0 {
+		t.waitCtx, t.waitCancel = context.WithTimeout(context.Background(), t.waitTimeout)
+	}
+	if d := activeDetector.Load(); d != nil {
+		d.track(t)
+	}
+	t.observer.OnCreate(t.info())
+	return t
+}
+
+// funcName returns the symbol name of fn, as reported by runtime.FuncForPC.
+func funcName(fn reflect.Value) string {
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
+
+// pkgPrefix is the prefix of the symbols of this package, used to skip its frames in callerSite.
+var pkgPrefix = reflect.TypeOf(task{}).PkgPath() + "."
+
+// siteCache maps the program counters seen by callerSite to the file:line of their first frame outside
+// this package, or to the empty string if all their frames are inside it, so that each is resolved only once.
+var siteCache = struct {
+	sync.RWMutex
+	byPC map[uintptr]string
+}{byPC: make(map[uintptr]string)}
+
+// callerSite returns the file:line of the first caller outside this package,
+// so that tasks created by helpers such as Reduce point at the user's code.
+// Test files of this package count as outside callers.
+// The stack is walked a few frames at a time, since the site is usually among the first ones.
+func callerSite() string {
+	var pcs [4]uintptr
+	for skip := 2; skip < 2+8*len(pcs); skip += len(pcs) {
+		n := runtime.Callers(skip, pcs[:])
+		for _, pc := range pcs[:n] {
+			siteCache.RLock()
+			site, ok := siteCache.byPC[pc]
+			siteCache.RUnlock()
+			if !ok {
+				site = resolveSite(pc)
+				siteCache.Lock()
+				siteCache.byPC[pc] = site
+				siteCache.Unlock()
+			}
+			if site != "" {
+				return site
+			}
+		}
+		if n < len(pcs) {
+			break
+		}
+	}
+	return "unknown"
+}
+
+// resolveSite returns the file:line of the first frame of pc outside this package, including inlined frames,
+// or the empty string if there is none.
+func resolveSite(pc uintptr) string {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPrefix) || strings.HasSuffix(f.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// describe returns the task's name, or its function if unnamed, with its creation site.
+func (t *task) describe() string {
+	name := t.name
+	if name == "" {
+		name = t.fn
+	}
+	return fmt.Sprintf("%q (created at %s)", name, filepath.Base(t.site))
+}
+
+// profilerLabels is whether tasks attach pprof labels while their function runs, as set by SetProfilerLabels.
+var profilerLabels atomic.Bool
+
+// SetProfilerLabels sets whether the name, function and creation site of each task are attached as pprof labels
+// (pas.task, pas.func and pas.site) to the goroutine running its function, so that profiles can be filtered by task.
+// It is off by default, since labelling allocates on every call.
+func SetProfilerLabels(on bool) {
+	profilerLabels.Store(on)
+}
+
+// callArgs returns the arguments passed to the task's function, starting with ctx if it is injected.
+func (t *task) callArgs(ctx context.Context, resolvedArgs []reflect.Value) []reflect.Value {
+	if !t.injectCtx {
+		return resolvedArgs
+	}
+	injected := ctx // Copied so that ctx only escapes when it is injected
+	return append([]reflect.Value{reflect.ValueOf(&injected).Elem()}, resolvedArgs...)
+}
+
+// invoke calls fv with in, attaching the task's pprof labels to the goroutine if enabled.
+// If the task has an execution budget, fv runs on another goroutine; once the budget is exceeded,
+// invoke returns without waiting for it, with a channel closed once it returns.
+func (t *task) invoke(ctx context.Context, fv reflect.Value, in []reflect.Value) ([]reflect.Value, <-chan struct{}) {
+	var results []reflect.Value // Left to the function's goroutine once the budget is exceeded
+	call := func() {
+		results = fv.Call(in)
+	}
+	if profilerLabels.Load() {
+		call = func() {
+			pprof.Do(ctx, t.labels(), func(context.Context) {
+				results = fv.Call(in)
+			})
+		}
+	}
+	if t.timeout <= 0 {
+		call()
+	} else if detached := callWithin(ctx, call); detached != nil {
+		return nil, detached
+	}
+	return results, nil
+}
+
+// labels returns the pprof labels attached to the goroutine while the task's function runs.
+func (t *task) labels() pprof.LabelSet {
+	if t.name == "" {
+		return pprof.Labels("pas.func", t.fn, "pas.site", t.site)
+	}
+	return pprof.Labels("pas.task", t.name, "pas.func", t.fn, "pas.site", t.site)
+}
+
+// info returns the description of the task passed to observers.
+func (t *task) info() TaskInfo {
+	return TaskInfo{
+		ID:        t.id,
+		Name:      t.name,
+		Site:      t.site,
+		Func:      t.fn,
+		NumArgs:   t.numArgs,
+		Sync:      t.sync,
+		Attempt:   t.attempt,
+		Throttled: t.throttled,
+		Created:   t.created,
+		Logger:    t.logger,
+	}
+}
+
+// node returns a snapshot of the task as a graph Node.
+func (t *task) node() Node {
+	return Node{
+		ID:           t.id,
+		Name:         t.name,
+		Site:         t.site,
+		Func:         t.fn,
+		NumArgs:      t.numArgs,
+		Sync:         t.sync,
+		Created:      t.created,
+		ArgsResolved: t.argsResolved,
+		Started:      t.started,
+		Finished:     t.finished,
+	}
+}
+
+// rejectedError marks an error as coming from a rejected promise among the task's arguments,
+// rather than from the task itself.
+type rejectedError struct {
+	err error
+}
+
+// Error returns the error of the rejected promise.
+func (e *rejectedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error of the rejected promise.
+func (e *rejectedError) Unwrap() error {
+	return e.err
+}
+
+// await is called whenever the task encounters a promise among its arguments.
+// It records the dependency and waits for the promise to be settled, within the task's wait budget.
+func (t *task) await(p promiseTypeContract) (interface{}, error) {
+	if rec := activeRecorder.Load(); rec != nil {
+		rec.addEdge(p.promiseID(), t.id)
+	}
+	if d := activeDetector.Load(); d != nil {
+		defer d.taskBlocked(t.id, p.promiseID())()
+	}
+	if producer := p.producer(); producer != nil {
+		t.sched.waitFor(producer)
+		defer t.sched.waitFor(nil)
+		producer.inheritPriority(t.sched.get())
+	}
+	var timedOut <-chan struct{}
+	if t.waitCtx != nil {
+		timedOut = t.waitCtx.Done()
+	}
+	select {
+	case <-p.readyChan():
+	case <-timedOut:
+		return nil, t.timeoutError("waiting for arguments", t.waitTimeout)
+	}
+	value, err := p.get()
+	if err != nil {
+		return nil, &rejectedError{err}
+	}
+	return value, nil
+}
+
+// lateArgsErr returns the error of the first channel argument that ended with a failure, or nil.
+// Channels still open when the function returns are not waited for.
+func (t *task) lateArgsErr() error {
+	for _, failure := range t.lateArgs {
+		if err := failure(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schedule waits until the task may run an attempt, and returns the function to call once it is done.
+// It waits for its turn on its Strand, then for its RateLimiters, so that a throttled task holds
+// neither resources nor workers. It then acquires its resources, then a worker of its Executor,
+// and releases them in reverse order; the turn is held until the task is done.
+func (t *task) schedule() func() {
+	if t.turn != nil {
+		t.turn.wait(t)
+	}
+	t.throttle()
+	var releases []func()
+	if len(t.resources) > 0 {
+		releases = append(releases, acquireResources(t.resources))
+	}
+	if t.executor != nil {
+		releases = append(releases, t.executor.acquire(t))
+	}
+	if len(releases) == 0 {
+		return func() {}
+	}
+	return func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+}
+
+// afterReturn calls release once detached is closed, or right away if it is nil,
+// so that a function running past its budget keeps what it holds until it returns.
+func afterReturn(detached <-chan struct{}, release func()) {
+	if detached == nil {
+		release()
+		return
+	}
+	go func() {
+		<-detached
+		release()
+	}()
+}
+
+// stopWaiting releases the resources of the wait budget once the arguments are resolved.
+func (t *task) stopWaiting() {
+	if t.waitCancel != nil {
+		t.waitCancel()
+	}
+}
+
+// execContext returns the context passed to the task's function, bounded by the execution budget.
+// A context is only derived when it is injected or the task has a budget, to keep plain calls cheap.
+func (t *task) execContext() (context.Context, context.CancelFunc) {
+	if t.timeout > 0 {
+		return context.WithTimeout(t.ctx, t.timeout)
+	}
+	if t.injectCtx {
+		return context.WithCancel(t.ctx)
+	}
+	return t.ctx, func() {}
+}
+
+// timeoutError returns the error of a task that exceeded its budget for the given phase.
+func (t *task) timeoutError(phase string, budget time.Duration) error {
+	return fmt.Errorf("pas: task %s timed out after %v %s: %w", t.describe(), budget, phase, ErrTimeout)
+}
+
+// capturedPanic carries a panic recovered on another goroutine, with its original stack trace.
+type capturedPanic struct {
+	value interface{}
+	stack []byte
+}
+
+// startAttempt records the start of an attempt at calling the task's function and notifies its observers.
+func (t *task) startAttempt() {
+	t.attempt++
+	t.attemptEnded = false
+	t.started = time.Now()
+	if d := activeDetector.Load(); d != nil {
+		d.started(t.id)
+	}
+	t.observer.OnStart(t.info())
+}
+
+// endAttempt must be deferred after startAttempt, with a pointer to the attempt's error.
+// It records the end of the attempt and notifies the task's observers.
+// A panic is recovered and stored in *errp as a *PanicError.
+func (t *task) endAttempt(errp *error) {
+	t.finished = time.Now()
+	if r := recover(); r != nil {
+		*errp = t.recovered(r)
+	}
+	t.observer.OnFinish(t.info(), t.finished.Sub(t.started), *errp)
+	t.attemptEnded = true
+}
+
+// recovered reports a recovered panic to the task's observers and returns it as a *PanicError.
+func (t *task) recovered(r interface{}) error {
+	value, stack := r, debug.Stack()
+	if cp, ok := r.(capturedPanic); ok {
+		value, stack = cp.value, cp.stack
+	}
+	t.observer.OnPanic(t.info(), value, stack)
+	return &PanicError{Value: value, Stack: stack, Task: t.info()}
+}
+
+// finish must be deferred by the code running the task, with a pointer to the task's error.
+// It records the end of the task, and notifies its observers unless the last attempt already did.
+// A panic is recovered and stored in *errp as a *PanicError.
+func (t *task) finish(errp *error) {
+	if r := recover(); r != nil {
+		*errp = t.recovered(r)
+		t.attemptEnded = false
+	}
+	if !t.attemptEnded {
+		t.finished = time.Now()
+		var duration time.Duration
+		if !t.started.IsZero() {
+			duration = t.finished.Sub(t.started)
+		}
+		t.observer.OnFinish(t.info(), duration, *errp)
+	}
+	if t.turn != nil {
+		t.turn.release(t.detached)
+	}
+	for _, cleanup := range t.cleanups {
+		afterReturn(t.detached, cleanup)
+	}
+	if d := activeDetector.Load(); d != nil {
+		d.finished(t)
+	}
+
+	if rec := activeRecorder.Load(); rec != nil {
+		rec.addNode(t)
+	}
+	if tr := activeTracer.Load(); tr != nil {
+		tr.addTask(t)
+	}
+}
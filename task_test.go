@@ -0,0 +1,49 @@
+package pas
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestPromiseString verifies that a promise describes its name, creation site and state.
+func TestPromiseString(t *testing.T) {
+	release := make(chan struct{})
+	wait := func(n int) int {
+		<-release
+		return n
+	}
+	p := Async[int](wait, 7, WithName("seven"))
+	if s := p.String(); !strings.Contains(s, `"seven"`) || !strings.Contains(s, "task_test.go:") || !strings.HasSuffix(s, "pending") {
+		t.Errorf("Unexpected description of pending promise: %s", s)
+	}
+	close(release)
+	p.Get()
+	if s := p.String(); !strings.HasSuffix(s, "ready (7)") {
+		t.Errorf("Unexpected description of ready promise: %s", s)
+	}
+	if s := New(1).String(); !strings.HasPrefix(s, "Promise[int]#") || !strings.HasSuffix(s, ": ready (1)") {
+		t.Errorf("Unexpected description of New promise: %s", s)
+	}
+}
+
+// TestPanicErrorNamesTask verifies that panic errors name the task and its creation site.
+func TestPanicErrorNamesTask(t *testing.T) {
+	obs := newCountingObserver()
+	SetObserver(nil)
+	defer SetObserver(LogObserver{})
+
+	Async[int](Explode, 1, WithName("exploder"), WithObserver(obs))
+	<-obs.finished
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	var pe *PanicError
+	if len(obs.errs) != 1 || !errors.As(obs.errs[0], &pe) {
+		t.Fatalf("Expected a PanicError, got %v", obs.errs)
+	}
+	msg := pe.Error()
+	if !strings.Contains(msg, `"exploder"`) || !strings.Contains(msg, "task_test.go:") || !strings.Contains(msg, "boom") {
+		t.Errorf("Unexpected panic message: %s", msg)
+	}
+}
@@ -0,0 +1,76 @@
+package pas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// ErrTimeout is the error, possibly wrapped, of promises rejected because a task exceeded its time budget.
+// Use errors.Is(err, pas.ErrTimeout) to test for it.
+var ErrTimeout = errors.New("pas: timeout")
+
+// WithTimeout sets the execution budget of the task: the time its function may run,
+// not counting the time spent waiting for its arguments.
+// When the budget is exceeded, the promise is rejected with ErrTimeout and the function's context,
+// if it takes one, is cancelled. The function itself cannot be interrupted and keeps running in the background.
+func WithTimeout(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.timeout = d
+	})
+}
+
+// WithWaitTimeout sets the budget for waiting on the promises among the task's arguments,
+// counted from the call to Async or Sync.
+// When the budget is exceeded, the promise is rejected with ErrTimeout without running the function.
+func WithWaitTimeout(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.waitTimeout = d
+	})
+}
+
+// callWithin runs call on a new goroutine and waits for it to return until ctx is done.
+// It returns nil if call returned in time, or else a channel closed once call eventually returns.
+// A panic in call is propagated to the caller if it happens in time.
+func callWithin(ctx context.Context, call func()) <-chan struct{} {
+	done := make(chan interface{}, 1)
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		defer func() {
+			if r := recover(); r != nil {
+				done <- capturedPanic{value: r, stack: debug.Stack()}
+			}
+			close(done)
+		}()
+		call()
+	}()
+	select {
+	case r, ok := <-done:
+		if ok {
+			panic(r)
+		}
+		return nil
+	case <-ctx.Done():
+		return returned
+	}
+}
+
+// Timeout returns a new Promise settled like p, or rejected with ErrTimeout if p is not settled within d.
+// Usage example: value, err := p.Timeout(time.Second).Result()
+func (p *Promise[T]) Timeout(d time.Duration) *Promise[T] {
+	q := newPending[T]()
+	go func() {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-p.ready:
+			q.settle(p.value, p.err)
+		case <-timer.C:
+			q.reject(fmt.Errorf("pas: promise #%d not settled within %v: %w", p.id, d, ErrTimeout))
+		}
+	}()
+	return q
+}
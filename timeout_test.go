@@ -0,0 +1,126 @@
+package pas
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// SleepContext sleeps for d milliseconds, or until ctx is cancelled.
+func SleepContext(ctx context.Context, d int) int {
+	select {
+	case <-time.After(time.Duration(d) * time.Millisecond):
+		return d
+	case <-ctx.Done():
+		return -1
+	}
+}
+
+// TestExecutionTimeout verifies that a task exceeding its execution budget is rejected,
+// that its context is cancelled, and that dependents see the failure.
+func TestExecutionTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	slow := func(ctx context.Context, n int) int {
+		<-ctx.Done()
+		close(cancelled)
+		return n
+	}
+	p := Async[int](slow, 1, WithTimeout(20*time.Millisecond))
+	dependent := Async[int](Add, p, 1)
+
+	if err := p.Err(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+	if err := dependent.Err(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected dependent to fail with ErrTimeout, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the function's context to be cancelled")
+	}
+
+	// A fast task is unaffected by its budget
+	if val := Async[int](SleepContext, 1, WithTimeout(time.Second)).Get(); val != 1 {
+		t.Errorf("Expected 1, got %d", val)
+	}
+}
+
+// TestContextArgument verifies that a context is injected only when the first argument is omitted,
+// and that a context passed explicitly, as a Promise or as nil, is passed as is.
+func TestContextArgument(t *testing.T) {
+	f := func(ctx context.Context, n int) int {
+		if ctx == nil {
+			return -n
+		}
+		return n
+	}
+	if val := Async[int](f, 3).Get(); val != 3 {
+		t.Errorf("Expected an injected context, got %d", val)
+	}
+	if val := Async[int](f, Async[int](Square, 2), true, WithName("injected")).Get(); val != 4 {
+		t.Errorf("Expected an injected context with the recursive flag and an Option, got %d", val)
+	}
+	if val := Async[int](f, nil, 3).Get(); val != -3 {
+		t.Errorf("Expected the nil context to be passed, got %d", val)
+	}
+	if val := Async[int](f, New[context.Context](context.Background()), 3).Get(); val != 3 {
+		t.Errorf("Expected the context Promise to be resolved, got %d", val)
+	}
+}
+
+// TestWaitTimeout verifies that the time spent waiting on arguments has its own budget.
+func TestWaitTimeout(t *testing.T) {
+	called := false
+	slowArg := Async[int](SleepContext, 200)
+	p := Async[int](func(n int) int {
+		called = true
+		return n
+	}, slowArg, WithWaitTimeout(20*time.Millisecond), WithTimeout(time.Second))
+
+	if err := p.Err(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+	if called {
+		t.Errorf("Expected the function not to be called")
+	}
+
+	// Waiting does not count against the execution budget
+	q := Async[int](Add, Async[int](SleepContext, 50), 1, WithTimeout(20*time.Millisecond))
+	if val, err := q.Result(); err != nil || val != 51 {
+		t.Errorf("Expected 51, got %d (%v)", val, err)
+	}
+}
+
+// TestPanicRejectsPromise verifies that a panic rejects the promise and its dependents
+// instead of leaving them pending forever.
+func TestPanicRejectsPromise(t *testing.T) {
+	SetObserver(nil)
+	defer SetObserver(LogObserver{})
+
+	p := Async[int](Explode, 1)
+	dependent := Async[int](SumSlice, []*Promise[int]{New(1), p}, true)
+
+	var pe *PanicError
+	if err := dependent.Err(); !errors.As(err, &pe) || pe.Task.ID != p.id {
+		t.Fatalf("Expected the PanicError of the exploding task, got %v", err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected Get to panic on a rejected promise")
+		}
+	}()
+	p.Get()
+}
+
+// TestPromiseTimeout verifies the Promise.Timeout wrapper.
+func TestPromiseTimeout(t *testing.T) {
+	slow := Async[int](SleepContext, 200)
+	if err := slow.Timeout(10 * time.Millisecond).Err(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+	if val := New(3).Timeout(time.Second).Get(); val != 3 {
+		t.Errorf("Expected 3, got %d", val)
+	}
+}
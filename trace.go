@@ -0,0 +1,136 @@
+package pas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// activeTracer is the Tracer currently recording task execution, or nil.
+var activeTracer atomic.Pointer[Tracer]
+
+// Tracer records when each task was created, when its arguments finished resolving,
+// and when its function started and finished, for export in the Chrome trace-event format.
+type Tracer struct {
+	start time.Time
+	mu    sync.Mutex
+	spans []Node
+}
+
+// traceEvent is a single entry of the Chrome trace-event format.
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type traceEvent struct {
+	Name string                 `json:"name"`
+	Cat  string                 `json:"cat,omitempty"`
+	Ph   string                 `json:"ph"`
+	Ts   float64                `json:"ts"`
+	Dur  float64                `json:"dur,omitempty"`
+	Pid  int                    `json:"pid"`
+	Tid  int                    `json:"tid"`
+	ID   string                 `json:"id,omitempty"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// StartTracing creates a Tracer and makes it the active one.
+// Only one Tracer is active at a time; starting a new trace replaces the previous one.
+// Usage example:
+// tr := pas.StartTracing()
+// defer tr.Stop()
+func StartTracing() *Tracer {
+	tr := &Tracer{start: time.Now()}
+	activeTracer.Store(tr)
+	return tr
+}
+
+// Stop stops the Tracer from recording further tasks.
+// The tasks recorded so far remain available.
+func (tr *Tracer) Stop() {
+	activeTracer.CompareAndSwap(tr, nil)
+}
+
+// addTask records a finished task.
+func (tr *Tracer) addTask(t *task) {
+	n := t.node()
+	tr.mu.Lock()
+	tr.spans = append(tr.spans, n)
+	tr.mu.Unlock()
+}
+
+// WriteJSON writes the recorded tasks to w as a Chrome trace-event JSON file,
+// which can be loaded in chrome://tracing or https://ui.perfetto.dev.
+// Each task's execution is shown on the track of the worker that ran it.
+// The time a task spent blocked on its arguments, and the time it spent ready but not yet running,
+// are shown as asynchronous spans alongside the worker tracks.
+func (tr *Tracer) WriteJSON(w io.Writer) error {
+	tr.mu.Lock()
+	spans := make([]Node, len(tr.spans))
+	copy(spans, tr.spans)
+	tr.mu.Unlock()
+
+	// Assign each execution to the lowest-numbered worker that is free at its start time
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Started.Before(spans[j].Started) })
+	var busyUntil []time.Time
+	workers := make([]int, len(spans))
+	for i, s := range spans {
+		if s.Started.IsZero() {
+			workers[i] = -1
+			continue
+		}
+		workers[i] = len(busyUntil)
+		for w, until := range busyUntil {
+			if !until.After(s.Started) {
+				workers[i] = w
+				break
+			}
+		}
+		if workers[i] == len(busyUntil) {
+			busyUntil = append(busyUntil, time.Time{})
+		}
+		busyUntil[workers[i]] = s.Finished
+	}
+
+	ts := func(t time.Time) float64 {
+		return float64(t.Sub(tr.start).Nanoseconds()) / 1e3
+	}
+	events := []traceEvent{{Name: "process_name", Ph: "M", Pid: 1, Args: map[string]interface{}{"name": "pas"}}}
+	for w := range busyUntil {
+		events = append(events, traceEvent{
+			Name: "thread_name", Ph: "M", Pid: 1, Tid: w + 1,
+			Args: map[string]interface{}{"name": fmt.Sprintf("worker %d", w)},
+		})
+	}
+	for i, s := range spans {
+		name := s.shortName()
+		args := map[string]interface{}{"id": s.ID, "name": s.Name, "func": s.Func, "site": s.Site, "args": s.NumArgs, "sync": s.Sync}
+		id := fmt.Sprint(s.ID)
+		if !s.ArgsResolved.IsZero() {
+			events = append(events,
+				traceEvent{Name: "blocked " + name, Cat: "wait", Ph: "b", Ts: ts(s.Created), Pid: 1, ID: id, Args: args},
+				traceEvent{Name: "blocked " + name, Cat: "wait", Ph: "e", Ts: ts(s.ArgsResolved), Pid: 1, ID: id},
+			)
+		}
+		if workers[i] < 0 {
+			continue
+		}
+		if s.Started.After(s.ArgsResolved) {
+			events = append(events,
+				traceEvent{Name: "queued " + name, Cat: "queue", Ph: "b", Ts: ts(s.ArgsResolved), Pid: 1, ID: id},
+				traceEvent{Name: "queued " + name, Cat: "queue", Ph: "e", Ts: ts(s.Started), Pid: 1, ID: id},
+			)
+		}
+		events = append(events, traceEvent{
+			Name: name, Cat: "run", Ph: "X", Ts: ts(s.Started), Dur: ts(s.Finished) - ts(s.Started),
+			Pid: 1, Tid: workers[i] + 1, Args: args,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
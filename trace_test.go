@@ -0,0 +1,59 @@
+package pas
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestTracerWriteJSON verifies that the Tracer produces a valid Chrome trace-event file
+// with one execution span per task and a named track per worker.
+func TestTracerWriteJSON(t *testing.T) {
+	tr := StartTracing()
+	arr := MakeSlice[int](4)
+	for i := range arr {
+		arr[i] = Async[int](Square, i)
+	}
+	Sync[int](SumSlice, arr, true)
+	tr.Stop()
+
+	var buf bytes.Buffer
+	if err := tr.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name string                 `json:"name"`
+			Ph   string                 `json:"ph"`
+			Tid  int                    `json:"tid"`
+			Args map[string]interface{} `json:"args"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("Invalid trace JSON: %v\n%s", err, buf.String())
+	}
+
+	runs, workers, blocked := 0, 0, 0
+	for _, e := range trace.TraceEvents {
+		switch {
+		case e.Ph == "X":
+			runs++
+			if e.Tid < 1 {
+				t.Errorf("Expected execution span on a worker track, got tid %d", e.Tid)
+			}
+		case e.Ph == "M" && e.Name == "thread_name":
+			workers++
+		case e.Ph == "b":
+			blocked++
+		}
+	}
+	if runs != 5 {
+		t.Errorf("Expected 5 execution spans, got %d", runs)
+	}
+	if workers < 1 || workers > 5 {
+		t.Errorf("Expected between 1 and 5 worker tracks, got %d", workers)
+	}
+	if blocked < 5 {
+		t.Errorf("Expected at least 5 blocked spans, got %d", blocked)
+	}
+}